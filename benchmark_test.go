@@ -1,6 +1,10 @@
 package validate
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
 
 func BenchmarkFieldSuccess(b *testing.B) {
 	v := New(M{
@@ -27,3 +31,75 @@ func BenchmarkFieldSuccessParallel(b *testing.B) {
 		}
 	})
 }
+
+type benchUser struct {
+	Name   string `json:"name" validate:"required|string|minLen:4|maxLen:32" filter:"trim"`
+	Email  string `json:"email" validate:"required|email" message:"email is invalid"`
+	Age    int    `json:"age" validate:"required|int|min:1|max:99"`
+	Status int    `json:"status" validate:"in:1,2,3"`
+	Info   struct {
+		City string `json:"city" validate:"required|minLen:2"`
+		Zip  string `json:"zip" validate:"isNumber|len:6"`
+	}
+}
+
+func newBenchUser() *benchUser {
+	u := &benchUser{Name: "inhere", Email: "some@e.com", Age: 23, Status: 1}
+	u.Info.City = "chengdu"
+	u.Info.Zip = "610000"
+	return u
+}
+
+func BenchmarkStructCached(b *testing.B) {
+	u := newBenchUser()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = Struct(u).Validate()
+	}
+}
+
+// for compare: clear the struct cache on every loop, the struct tags will be re-parsed.
+func BenchmarkStructNoCache(b *testing.B) {
+	u := newBenchUser()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		defRegistry.structs.reset()
+		_ = Struct(u).Validate()
+	}
+}
+
+func BenchmarkStructCachedParallel(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		u := newBenchUser()
+		for pb.Next() {
+			_ = Struct(u).Validate()
+		}
+	})
+}
+
+func BenchmarkSchema_ValidateCSV(b *testing.B) {
+	s := NewSchema(func(v *Validation) {
+		v.StringRules(MS{"name": "required|minLen:2", "age": "required|int|between:1,120"})
+	})
+
+	var sb strings.Builder
+	sb.WriteString("name,age\n")
+	for i := 0; i < 1000; i++ {
+		sb.WriteString("inhere,20\n")
+	}
+	src := sb.String()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = s.ValidateCSV(strings.NewReader(src), func(opt *CSVOption) {
+			opt.Types = map[string]reflect.Kind{"age": reflect.Int}
+		})
+	}
+}
@@ -1,53 +1,260 @@
 package validate
 
 import (
-	"database/sql/driver"
-	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
-/*
-// TODO: idea for optimize ...
-// - use pool for Validation
-// - cache struct reflect value
+// structKey is the cache key of a struct type.
+// the tag names are part of the key, since them can be changed by Config().
+type structKey struct {
+	typ reflect.Type
+	// tag names
+	validateTag string
+	filterTag   string
+	fieldTag    string
+	messageTag  string
+	keyTag      string
+}
 
-// Usage:
-// // use a single instance of Validate, it caches struct info
-// vf := validate.NewFactory()
-// v := vf.New(data)
-// v.Validate()
+// cStruct is the cached info of a struct type. it is readonly after created.
+type cStruct struct {
+	typ    reflect.Type
+	fields []*cField
+}
 
-// global factory for create Validation
-var gf = newFactory()
+// cField is the cached tag info of a struct field
+type cField struct {
+	// index of the field in the struct
+	index int
+	name  string
+	// is anonymous field
+	anonymous bool
+	// field type, has been removed pointer
+	typ reflect.Type
+	// parsed validate rules from the validate tag
+	rules []*ruleItem
+	// parsed map key rules from the key tag
+	keyRules []*ruleItem
+	// the error of parse the validate or key tag
+	ruleErr *RuleParseError
+	// filter rule from the filter tag
+	filterRule string
+	// field translate name. eg: `json:"user_name"`
+	transName string
+	// custom error messages from the message tag.
+	// key is validator name. eg: {"required": "name is required"}
+	messages MS
+}
 
-// factory for create Validation instances
+// factory for create Validation instances. it caches parsed struct tags by type.
+// each registry has own factory, the alias names in the tags are resolved by the registry.
 type factory struct {
-	// pool for Validation instances
-	pool sync.Pool
-	// m atomic.Value // map[reflect.Type]*cStruct
+	reg *Registry
+	// lock for write cache
+	mu sync.Mutex
+	// readonly cache map, replace it on write.
+	m atomic.Value // map[structKey]*cStruct
+}
+
+func newFactory(reg *Registry) *factory {
+	f := &factory{reg: reg}
+	f.m.Store(make(map[structKey]*cStruct))
+	return f
 }
 
-func newFactory() *factory {
-	f := &factory{}
-	f.pool.New = func() interface{} {
-		return newValidation(nil)
+// structOf get cached struct info, will parse and cache it on not found.
+func (f *factory) structOf(key structKey) *cStruct {
+	if cs, ok := f.m.Load().(map[structKey]*cStruct)[key]; ok {
+		return cs
 	}
 
-	return f
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// maybe has been stored by other goroutine
+	old := f.m.Load().(map[structKey]*cStruct)
+	if cs, ok := old[key]; ok {
+		return cs
+	}
+
+	cs := parseStruct(f.reg, key)
+
+	// copy on write
+	mp := make(map[structKey]*cStruct, len(old)+1)
+	for k, v := range old {
+		mp[k] = v
+	}
+	mp[key] = cs
+	f.m.Store(mp)
+
+	return cs
+}
+
+// size of the cached structs
+func (f *factory) size() int {
+	return len(f.m.Load().(map[structKey]*cStruct))
 }
 
-func (f *factory) get() *Validation {
-	v := f.pool.Get().(*Validation)
-	// TODO clear something
-	return v
+// reset clear all cached struct info.
+// it is called on the validators or alias names of the registry are changed.
+func (f *factory) reset() {
+	f.mu.Lock()
+	f.m.Store(make(map[structKey]*cStruct))
+	f.mu.Unlock()
+}
+
+// parse the struct type tags, only parse the top level fields.
+func parseStruct(reg *Registry, key structKey) *cStruct {
+	vt := key.typ
+	cs := &cStruct{typ: vt}
+
+	for i := 0; i < vt.NumField(); i++ {
+		fv := vt.Field(i)
+
+		// skip don't exported field
+		name := fv.Name
+		if name[0] >= 'a' && name[0] <= 'z' {
+			continue
+		}
+
+		cf := &cField{
+			index:     i,
+			name:      name,
+			anonymous: fv.Anonymous,
+			typ:       removeTypePtr(fv.Type),
+		}
+
+		// validate rule
+		vRule := fv.Tag.Get(key.validateTag)
+		if vRule != "" {
+			cf.rules, cf.ruleErr = parseRegistryRule(reg, vRule)
+		}
+
+		// map key rule. eg: `validateKey:"isAlphaDash|maxLen:32"`
+		if key.keyTag != "" && cf.ruleErr == nil {
+			if kRule := fv.Tag.Get(key.keyTag); kRule != "" {
+				cf.keyRules, cf.ruleErr = parseRegistryRule(reg, kRule)
+			}
+		}
+
+		// filter rule
+		cf.filterRule = fv.Tag.Get(key.filterTag)
+
+		// load field translate name. eg: `json:"user_name"`
+		if key.fieldTag != "" {
+			cf.transName = fv.Tag.Get(key.fieldTag)
+		}
+
+		// load custom error messages.
+		// eg: `message:"required:name is required|minLen:name min len is %d"`
+		if key.messageTag != "" {
+			if errMsg := fv.Tag.Get(key.messageTag); errMsg != "" {
+				cf.messages = parseMessagesTag(reg, vRule, errMsg)
+			}
+		}
+
+		cs.fields = append(cs.fields, cf)
+	}
+
+	return cs
 }
 
-func (f *factory) put(v *Validation) {
-	f.pool.Put(v)
+// addTo add the field rules, filter rule, translate name and messages to the Validation
+func (cf *cField) addTo(v *Validation, name string, fMap map[string]string) {
+	// invalid rule in the tags
+	if cf.ruleErr != nil {
+		v.addRuleParseError(name, cf.ruleErr)
+	}
+
+	// the conditions in the rules are relative to the struct of the field. eg: "Items.*" for "Items.*.TaxID"
+	structPath, _ := splitFieldPath(name)
+
+	// validate rules
+	if len(cf.rules) > 0 {
+		v.addRuleItems(name, structPath, cf.rules)
+	}
+
+	// map key rules
+	if len(cf.keyRules) > 0 {
+		v.addRuleItems(name+keyPathSuffix, structPath, cf.keyRules)
+	}
+
+	// filter rule
+	if cf.filterRule != "" {
+		v.FilterRule(name, cf.filterRule)
+	}
+
+	// field translate name. eg: `json:"user_name"`
+	if cf.transName != "" {
+		fMap[name] = cf.transName
+	}
+
+	// custom error messages.
+	// eg: `message:"required:name is required|minLen:name min len is %d"`
+	for vName, errMsg := range cf.messages {
+		v.trans.AddMessage(name+"."+vName, errMsg)
+		// also use for the map key rules
+		if len(cf.keyRules) > 0 {
+			v.trans.AddMessage(name+keyPathSuffix+"."+vName, errMsg)
+		}
+	}
 }
-*/
 
-func test() {
-	i32 := driver.Int32
+// parseMessagesTag parse the message tag, returns validator name to message map.
+// the alias names are resolved by the registry.
+// eg: `message:"required:name is required|minLen:name min len is %d"`
+func parseMessagesTag(reg *Registry, vRule, vMsg string) MS {
+	var vName string
+	msgMap := make(MS)
+
+	// only one message, use for first validator.
+	// eg: `message:"name is required"`
+	if !strings.ContainsRune(vMsg, '|') {
+		// eg: `message:"required:name is required"`
+		if strings.ContainsRune(vMsg, ':') {
+			nodes := strings.SplitN(vMsg, ":", 2)
+			vName = strings.TrimSpace(nodes[0])
+			// first is validator name
+			vMsg = strings.TrimSpace(nodes[1])
+		}
+
+		if vName == "" {
+			// eg `validate:"required|date"`
+			vName = vRule
+			if strings.ContainsRune(vRule, '|') {
+				nodes := strings.SplitN(vRule, "|", 2)
+				// use first validator name
+				vName = nodes[0]
+			}
 
-	fmt.Println(i32)
+			// has params for validator: "minLen:5"
+			if strings.ContainsRune(vName, ':') {
+				nodes := strings.SplitN(vRule, ":", 2)
+				// use first validator name
+				vName = nodes[0]
+			}
+		}
+
+		msgMap[vName] = vMsg
+		return msgMap
+	}
+
+	// multi message for validators
+	// eg: `message:"required:name is required | minLen:name min len is %d"`
+	msgNodes := strings.Split(vMsg, "|")
+	for _, validatorWithMsg := range msgNodes {
+		// validatorWithMsg eg: "required:name is required"
+		nodes := strings.SplitN(validatorWithMsg, ":", 2)
+
+		validator := strings.TrimSpace(nodes[0])
+		if rName, has := reg.realName(validator); has {
+			validator = rName
+		}
+
+		msgMap[validator] = strings.TrimSpace(nodes[1])
+	}
+	return msgMap
 }
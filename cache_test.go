@@ -0,0 +1,102 @@
+package validate
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFactory_structOf(t *testing.T) {
+	is := assert.New(t)
+	defRegistry.structs.reset()
+	is.Equal(0, defRegistry.structs.size())
+
+	u := &UserForm{Name: "inhere"}
+	v := Struct(u)
+	is.True(v.Trans().HasMessage("Name.required"))
+	// UserForm, ExtraInfo will be parsed by type, rules of the Extra use wildcard path.
+	is.Equal(2, defRegistry.structs.size())
+	var fields []string
+	for _, r := range v.rules {
+		fields = append(fields, r.fields...)
+	}
+	is.Contains(fields, "Extra.*.Github")
+
+	u.Extra = []ExtraInfo{{"xxx", 4}}
+	v1 := Struct(u)
+	is.Equal(2, defRegistry.structs.size())
+	is.Equal(len(v.rules), len(v1.rules))
+
+	// will not re-parse
+	Struct(u)
+	is.Equal(2, defRegistry.structs.size())
+
+	// custom tag name is another cache key
+	sd, err := FromStruct(u)
+	is.NoError(err)
+	sd.ValidateTag = "v"
+	sd.Create()
+	is.Equal(4, defRegistry.structs.size())
+
+	// the cached rule args should not be changed by validate.
+	key := structKey{typ: sd.valueTpy, validateTag: validateTag, filterTag: filterTag, fieldTag: fieldTag, messageTag: messageTag, keyTag: keyTag}
+	cs := defRegistry.structs.structOf(key)
+	is.Equal("Status", cs.fields[6].name)
+	v1.Validate()
+	is.Equal("Extra.0.Status1", cs.fields[6].rules[1].args[0])
+}
+
+func TestFactory_concurrent(t *testing.T) {
+	defRegistry.structs.reset()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u := &UserForm{Name: "inhere", Extra: []ExtraInfo{{"xxx", 4}}}
+			Struct(u).Validate()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 2, defRegistry.structs.size())
+}
+
+func TestFactory_registry(t *testing.T) {
+	is := assert.New(t)
+	defRegistry.structs.reset()
+
+	// the struct info is cached on the registry, will be released with it.
+	reg := NewRegistry()
+	reg.Struct(&UserForm{Name: "inhere"})
+	is.Equal(2, reg.structs.size())
+	is.Equal(0, defRegistry.structs.size())
+
+	// the cache is cleared on the validators or alias names are changed.
+	reg.AddAlias("oneOf", "enum")
+	is.Equal(0, reg.structs.size())
+
+	reg.Struct(&UserForm{Name: "inhere"})
+	reg.AddValidator("phone", func(val string) bool { return val != "" })
+	is.Equal(0, reg.structs.size())
+
+	// add filter will not clear the cache
+	reg.Struct(&UserForm{Name: "inhere"})
+	reg.AddFilter("myTrim", strings.TrimSpace)
+	is.Equal(2, reg.structs.size())
+}
+
+func TestParseMessagesTag(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal(MS{"required": "name is required"}, parseMessagesTag(defRegistry, "required|minLen:5", "name is required"))
+	is.Equal(MS{"minLen": "too short"}, parseMessagesTag(defRegistry, "minLen:5|required", "too short"))
+	is.Equal(MS{"minLength": "too short"}, parseMessagesTag(defRegistry, "required", "minLength:too short"))
+	is.Equal(
+		MS{"required": "name is required", "minLength": "min len is %d"},
+		parseMessagesTag(defRegistry, "required|minLen:5", "required:name is required | minLen:min len is %d"),
+	)
+}
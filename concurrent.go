@@ -0,0 +1,234 @@
+package validate
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+/*************************************************************
+ * Concurrent validating:
+ *  - the rules are grouped by field, each field is a task.
+ *  - the rules of one field run in order, the tasks run in parallel.
+ *  - the errors are merged by the sequential order of the rules.
+ *************************************************************/
+
+// fieldTask is the rules of one field, will run in a worker.
+type fieldTask struct {
+	field string
+	items []taskItem
+	// the shadow Validation for collect result
+	w *Validation
+	// the sequence of each error in w.fieldErrors
+	errSeqs []int
+	// the task is aborted by the context done
+	aborted bool
+	// recovered panic value from the validator
+	panicVal interface{}
+}
+
+// taskItem is a rule of the field task.
+type taskItem struct {
+	rule *Rule
+	// sequence number in the sequential validating
+	seq int
+}
+
+// validateConcurrently apply the rules for the fields in parallel, use Concurrency workers.
+func (v *Validation) validateConcurrently() {
+	tasks := v.buildFieldTasks()
+	if len(tasks) == 0 {
+		return
+	}
+
+	// the minimum sequence of stop on error. workers skip the rules after it.
+	var stopSeq int64 = -1
+	minStopSeq := func() int64 { return atomic.LoadInt64(&stopSeq) }
+	setStopSeq := func(seq int64) {
+		for {
+			old := atomic.LoadInt64(&stopSeq)
+			if old != -1 && old <= seq {
+				return
+			}
+			if atomic.CompareAndSwapInt64(&stopSeq, old, seq) {
+				return
+			}
+		}
+	}
+
+	workers := v.Concurrency
+	if workers > len(tasks) {
+		workers = len(tasks)
+	}
+
+	var wg sync.WaitGroup
+	ch := make(chan *fieldTask)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range ch {
+				t.run(minStopSeq, setStopSeq)
+			}
+		}()
+	}
+
+	for _, t := range tasks {
+		ch <- t
+	}
+	close(ch)
+	wg.Wait()
+
+	v.mergeFieldTasks(tasks, minStopSeq())
+}
+
+// group the rules by the field, keep the order of the field first appeared.
+func (v *Validation) buildFieldTasks() (tasks []*fieldTask) {
+	var seq int
+	taskMap := make(map[string]*fieldTask)
+
+	for _, r := range v.rules {
+		// scene name is not match. skip the rule
+		if r.scene != "" && r.scene != v.scene {
+			seq += len(r.fields)
+			continue
+		}
+
+		// resolve the validator on the current goroutine.
+		// NOTICE: validatorMeta() maybe write the validators map.
+		if r.anyOf != nil {
+			for _, alt := range r.anyOf {
+				v.resolveValidator(alt)
+			}
+		} else {
+			v.resolveValidator(r)
+		}
+
+		for _, field := range r.fields {
+			t, ok := taskMap[field]
+			if !ok {
+				t = &fieldTask{field: field}
+				taskMap[field] = t
+				tasks = append(tasks, t)
+			}
+
+			t.items = append(t.items, taskItem{rule: r, seq: seq})
+			seq++
+		}
+	}
+
+	for _, t := range tasks {
+		t.w = v.shadow()
+	}
+	return
+}
+
+// resolveValidator check the validator of the rule is exists.
+func (v *Validation) resolveValidator(r *Rule) {
+	if r.checkFuncMeta == nil && !isFileValidator(r.realName) && r.realName != "-" && r.realName != "safe" {
+		if v.validatorMeta(r.realName) == nil {
+			panicf("the validator '%s' does not exist", r.validator)
+		}
+	}
+}
+
+// run the rules of the field in order.
+func (t *fieldTask) run(minStopSeq func() int64, setStopSeq func(seq int64)) {
+	defer func() {
+		if err := recover(); err != nil {
+			t.panicVal = err
+		}
+	}()
+
+	w := t.w
+	for _, it := range t.items {
+		// has been stopped by a previous rule of other field
+		if stop := minStopSeq(); stop != -1 && int64(it.seq) > stop {
+			return
+		}
+
+		// the context is canceled or deadline exceeded.
+		if w.ctxDone() {
+			t.aborted = true
+			return
+		}
+
+		// has beforeFunc and it return FALSE, skip validate
+		if it.rule.beforeFunc != nil && !it.rule.beforeFunc(w) {
+			continue
+		}
+
+		errNum := len(w.fieldErrors)
+		stop := it.rule.applyOne(w, t.field)
+		for i := errNum; i < len(w.fieldErrors); i++ {
+			t.errSeqs = append(t.errSeqs, it.seq)
+		}
+
+		// aborted in the middle of the wildcard paths
+		if w.aborted {
+			t.aborted = true
+			return
+		}
+
+		if stop {
+			setStopSeq(int64(it.seq))
+			return
+		}
+	}
+}
+
+// merge the task results to the Validation, the errors are sorted by the sequence.
+func (v *Validation) mergeFieldTasks(tasks []*fieldTask, stopSeq int64) {
+	type seqError struct {
+		seq int
+		fe  *FieldError
+	}
+
+	var aborted bool
+	var errs []seqError
+	for _, t := range tasks {
+		// re-panic on the current goroutine
+		if t.panicVal != nil {
+			panic(t.panicVal)
+		}
+
+		aborted = aborted || t.aborted
+		for i, fe := range t.w.fieldErrors {
+			if stopSeq == -1 || int64(t.errSeqs[i]) <= stopSeq {
+				errs = append(errs, seqError{t.errSeqs[i], fe})
+			}
+		}
+
+		for key, val := range t.w.safeData {
+			v.safeData[key] = val
+		}
+		for key, val := range t.w.filteredData {
+			v.filteredData[key] = val
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].seq < errs[j].seq
+	})
+
+	for _, se := range errs {
+		v.AddFieldError(se.fe)
+	}
+
+	if aborted {
+		v.addContextError(v.ctx.Err())
+	}
+}
+
+// shadow create a Validation for the worker. it shares the readonly settings,
+// but has own result state.
+func (v *Validation) shadow() *Validation {
+	w := *v
+	w.Concurrency = 0
+	w.Errors = make(Errors)
+	w.fieldErrors = nil
+	w.hasError = false
+	w.safeData = make(map[string]interface{})
+	w.filteredData = make(map[string]interface{})
+	return &w
+}
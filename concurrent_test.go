@@ -0,0 +1,118 @@
+package validate
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidation_Concurrency(t *testing.T) {
+	is := assert.New(t)
+	data := M{
+		"name":  "ab",
+		"age":   10,
+		"email": "invalid",
+		"city":  "",
+		"items": []interface{}{M{"price": 0}, M{"price": 3}},
+	}
+
+	newV := func(stopOnError bool, concurrency int) *Validation {
+		v := Map(data)
+		v.StopOnError = stopOnError
+		v.Concurrency = concurrency
+		v.StringRules(MS{
+			"name":          "required|minLen:3",
+			"age":           "required|min:18",
+			"email":         "email",
+			"city":          "required",
+			"items.*.price": "required|min:1",
+		})
+		v.AddRule("name", "maxLen", 1)
+		return v
+	}
+
+	for _, stop := range []bool{false, true} {
+		seq := newV(stop, 0)
+		is.False(seq.Validate())
+
+		for i := 0; i < 20; i++ {
+			v := newV(stop, 3)
+			is.False(v.Validate())
+			is.Equal(seq.FieldErrors(), v.FieldErrors())
+			is.Equal(seq.Errors, v.Errors)
+		}
+	}
+
+	// passed
+	v := Map(M{"name": "inhere", "age": 20})
+	v.Concurrency = 2
+	v.StringRules(MS{"name": "required|minLen:3", "age": "int|min:18"})
+	is.True(v.Validate())
+	is.Equal("inhere", v.SafeVal("name"))
+	is.Equal(20, v.SafeVal("age"))
+}
+
+func TestValidation_Concurrency_workers(t *testing.T) {
+	is := assert.New(t)
+
+	var running, maxRunning int32
+	slowCheck := func(val interface{}) bool {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			old := atomic.LoadInt32(&maxRunning)
+			if n <= old || atomic.CompareAndSwapInt32(&maxRunning, old, n) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return val != "bad"
+	}
+
+	mp := M{}
+	for _, name := range []string{"f0", "f1", "f2", "f3", "f4", "f5"} {
+		mp[name] = "ok"
+	}
+	mp["f4"] = "bad"
+
+	v := Map(mp)
+	v.Concurrency = 2
+	v.StopOnError = false
+	v.AddValidator("slowCheck", slowCheck)
+	v.AddRule("f0,f1,f2,f3,f4,f5", "slowCheck")
+
+	is.False(v.Validate())
+	is.LessOrEqual(atomic.LoadInt32(&maxRunning), int32(2))
+	is.Equal([]string{"f4"}, v.FieldErrors().Fields())
+
+	// panic in the validator will be re-panic on the caller goroutine
+	v = Map(M{"name": "inhere", "age": 1})
+	v.Concurrency = 2
+	v.AddValidator("panicIt", func(val interface{}) bool {
+		panic("oops")
+	})
+	v.AddRule("name,age", "panicIt")
+	is.PanicsWithValue("oops", func() {
+		v.Validate()
+	})
+}
+
+func TestValidation_Concurrency_struct(t *testing.T) {
+	is := assert.New(t)
+
+	u := &UserForm{Name: "inhere", Code: "12"}
+	v := Struct(u)
+	v.Concurrency = 4
+	v.StopOnError = false
+	v.StringRule("Code", "default:2021|int")
+
+	seq := Struct(u)
+	seq.StopOnError = false
+	seq.StringRule("Code", "default:2021|int")
+
+	is.Equal(seq.Validate(), v.Validate())
+	is.Equal(seq.FieldErrors().Fields(), v.FieldErrors().Fields())
+}
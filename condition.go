@@ -0,0 +1,194 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// the name of the conditional item in the string rule.
+// eg: "when:type=company|required|len:18"
+const whenName = "when"
+
+/*************************************************************
+ * conditional rules
+ *************************************************************/
+
+// WhenRules is a block of the conditional rules, create by Validation.When().
+// the rules in the block only be applied on the condition returns true.
+type WhenRules struct {
+	v    *Validation
+	cond func(v *Validation) bool
+}
+
+// When create a block of the conditional rules. the cond allow:
+//
+// 	- string: the condition expression, see parseCondition(). eg: "type=company"
+// 	- func(v *Validation) bool: custom condition func
+//
+// Usage:
+// 	v.When("type=company").Rules(validate.MS{
+// 		"taxId": "required|len:18|isAlphaNum",
+// 	})
+// 	v.When("type=person").StringRule("idCard", "required|len:18")
+//
+// NOTICE: the conditional rules use the Rule.beforeFunc, and the "default" cannot be used in the block.
+func (v *Validation) When(cond interface{}) *WhenRules {
+	switch c := cond.(type) {
+	case string:
+		fn, err := parseCondition(c)
+		if err != nil {
+			panicf("invalid condition %q: %s", c, err.Error())
+		}
+		return &WhenRules{v: v, cond: fn}
+	case func(v *Validation) bool:
+		return &WhenRules{v: v, cond: c}
+	default:
+		panicf("the condition must be a string or func(v *Validation) bool, but got %T", cond)
+	}
+	return nil
+}
+
+// Rules add the string rules to the block.
+func (w *WhenRules) Rules(mp MS) *Validation {
+	w.v.addWhenRules(w.cond, func() {
+		w.v.StringRules(mp)
+	})
+	return w.v
+}
+
+// StringRule add a string rule for the field to the block.
+func (w *WhenRules) StringRule(field, rule string) *WhenRules {
+	w.v.addWhenRules(w.cond, func() {
+		w.v.StringRule(field, rule)
+	})
+	return w
+}
+
+// AddRule add a rule to the block.
+func (w *WhenRules) AddRule(fields, validator string, args ...interface{}) *Rule {
+	var rule *Rule
+	w.v.addWhenRules(w.cond, func() {
+		rule = w.v.AddRule(fields, validator, args...)
+	})
+	return rule
+}
+
+// addWhenRules set the condition for the rules added by the fn.
+func (v *Validation) addWhenRules(cond func(v *Validation) bool, fn func()) {
+	for _, r := range v.addBlockRules(fn) {
+		r.beforeFunc = andCondition(cond, r.beforeFunc)
+	}
+}
+
+// addFieldWhenRules set the condition of each field path for the rules added by the fn.
+// it is used for the fields of the nested struct, the condition is checked in the struct of the field path.
+func (v *Validation) addFieldWhenRules(cond func(v *Validation, field string) bool, fn func()) {
+	for _, r := range v.addBlockRules(fn) {
+		r.fieldCond = cond
+	}
+}
+
+// add the rules of a conditional block by the fn, returns the added rules.
+func (v *Validation) addBlockRules(fn func()) []*Rule {
+	start := len(v.rules)
+	defNum := len(v.defValues)
+	fn()
+
+	if len(v.defValues) != defNum {
+		panicf("the 'default' cannot be used in the conditional rules")
+	}
+	return v.rules[start:]
+}
+
+// andCondition returns a condition func of the cond1 and cond2.
+func andCondition(cond1, cond2 func(v *Validation) bool) func(v *Validation) bool {
+	if cond2 == nil {
+		return cond1
+	}
+
+	return func(v *Validation) bool {
+		return cond1(v) && cond2(v)
+	}
+}
+
+// condition is a parsed condition expression. eg: "type=company"
+type condition struct {
+	// the field name of the condition. eg: "type"
+	field string
+	// the operator: "", "!", "=", "!="
+	op     string
+	values []string
+}
+
+// parseCondition parse the condition expression to a func, the field of the condition is from the root.
+// see newCondition() for the syntax.
+func parseCondition(expr string) (func(v *Validation) bool, error) {
+	c, err := newCondition(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.checkRoot, nil
+}
+
+// newCondition parse the condition expression. allow:
+//
+// 	- "field": the field value is not empty
+// 	- "!field": the field value is empty or not exists
+// 	- "field=a,b": the field value is one of the values
+// 	- "field!=a,b": the field value is not any of the values, or not exists
+func newCondition(expr string) (*condition, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("the condition is empty")
+	}
+
+	c := &condition{}
+	if pos := strings.IndexByte(expr, '='); pos > 0 {
+		c.field, c.op = expr[:pos], "="
+		if expr[pos-1] == '!' {
+			c.field, c.op = expr[:pos-1], "!="
+		}
+		c.values = stringSplit(expr[pos+1:], ",")
+	} else if expr[0] == '!' {
+		c.field, c.op = expr[1:], "!"
+	} else {
+		c.field = expr
+	}
+
+	c.field = strings.TrimSpace(c.field)
+	if c.field == "" || strings.ContainsAny(c.field, "=! ") {
+		return nil, fmt.Errorf("the condition field name is invalid")
+	}
+	return c, nil
+}
+
+// check the condition by the value of the field path.
+func (c *condition) check(v *Validation, field string) bool {
+	val, has := v.Get(field)
+	switch c.op {
+	case "=":
+		return has && Enum(val, c.values)
+	case "!=":
+		return !has || !Enum(val, c.values)
+	case "!":
+		return !has || IsEmpty(val)
+	}
+	return has && !IsEmpty(val)
+}
+
+// checkRoot check the condition by the field from the root.
+func (c *condition) checkRoot(v *Validation) bool {
+	return c.check(v, c.field)
+}
+
+// inStruct returns the condition func for the fields of the struct, the condition field is relative to the struct.
+// the structPath is the path of the struct in the rule, the field is the real path of the checked field.
+// eg: the structPath "Items.*", the field "Items.0.TaxID", the condition "Type=company" checks the "Items.0.Type".
+func (c *condition) inStruct(structPath string) func(v *Validation, field string) bool {
+	depth := strings.Count(structPath, ".") + 1
+	return func(v *Validation, field string) bool {
+		nodes := strings.SplitN(field, ".", depth+1)
+		return c.check(v, strings.Join(nodes[:depth], ".")+"."+c.field)
+	}
+}
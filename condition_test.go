@@ -0,0 +1,177 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidation_When(t *testing.T) {
+	is := assert.New(t)
+
+	newV := func(data M) *Validation {
+		v := Map(data)
+		v.StopOnError = false
+		v.StringRule("type", "required|in:company,person")
+		v.When("type=company").Rules(MS{
+			"taxId": "required|len:18|isAlphaNum",
+		})
+		v.When("type=person").StringRule("idCard", "required|len:18")
+		return v
+	}
+
+	v := newV(M{"type": "company", "taxId": "ABCDEFGHIJ12345678"})
+	is.True(v.Validate())
+	is.Equal("ABCDEFGHIJ12345678", v.SafeVal("taxId"))
+
+	v = newV(M{"type": "company", "idCard": "abc"})
+	is.False(v.Validate())
+	is.Equal([]string{"taxId"}, v.FieldErrors().Fields())
+	is.Equal("taxId is required and not empty", v.Errors.One())
+
+	v = newV(M{"type": "person", "taxId": "abc"})
+	is.False(v.Validate())
+	is.Equal([]string{"idCard"}, v.FieldErrors().Fields())
+
+	// custom condition func
+	v = Map(M{"age": 16, "guardian": ""})
+	v.When(func(v *Validation) bool {
+		age, _ := v.Get("age")
+		return age.(int) < 18
+	}).AddRule("guardian", "required")
+	is.False(v.Validate())
+	is.Equal("guardian is required and not empty", v.Errors.One())
+
+	is.Panics(func() {
+		Map(M{}).When("=abc")
+	})
+	is.Panics(func() {
+		Map(M{}).When(123)
+	})
+	is.Panics(func() {
+		Map(M{}).When("type").StringRule("name", "default:tom")
+	})
+}
+
+func TestValidation_When_stringRule(t *testing.T) {
+	is := assert.New(t)
+
+	rule := "when:type=person|required|len:6|when:type!=person|minLen:10"
+	v := Map(M{"type": "person", "code": "abcdef"})
+	v.StringRule("code", rule)
+	is.True(v.Validate())
+
+	v = Map(M{"type": "company", "code": "abcdef"})
+	v.StringRule("code", rule)
+	is.False(v.Validate())
+	is.Equal("code min length is 10", v.Errors.One())
+
+	// the rules before the "when" are always applied
+	v = Map(M{"code": "ab"})
+	v.StringRule("code", "minLen:3|when:type|required")
+	is.False(v.Validate())
+
+	// in struct tags
+	type form struct {
+		Type  string `validate:"required"`
+		TaxID string `validate:"when:Type=company|required|len:18"`
+		Note  string `validate:"when:!Type|required"`
+	}
+
+	v = Struct(&form{Type: "company", TaxID: "123"})
+	is.False(v.Validate())
+	is.Equal([]string{"TaxID"}, v.FieldErrors().Fields())
+
+	v = Struct(&form{Type: "person"})
+	is.True(v.Validate())
+
+	_, err := parseStringRule("when|required")
+	is.Equal("missing the condition for the 'when'", err.Message)
+	_, err = parseStringRule("required|when:!=a")
+	is.Equal(15, err.Column)
+}
+
+type whenParty struct {
+	Type  string
+	TaxID string `validate:"when:Type=company|required|len:18"`
+}
+
+type whenOrder struct {
+	Type  string
+	Buyer whenParty
+	Items []whenParty
+}
+
+func TestValidation_When_nestedStruct(t *testing.T) {
+	is := assert.New(t)
+	taxID := "ABCDEFGHIJ12345678"
+
+	// the condition field is in the same struct
+	v := Struct(&whenOrder{Type: "company", Buyer: whenParty{Type: "person"}})
+	is.True(v.Validate())
+
+	v = Struct(&whenOrder{Buyer: whenParty{Type: "company"}})
+	is.False(v.Validate())
+	is.Equal([]string{"Buyer.TaxID"}, v.FieldErrors().Fields())
+
+	// each slice element checks its own condition
+	o := &whenOrder{
+		Type:  "company",
+		Buyer: whenParty{Type: "company", TaxID: taxID},
+		Items: []whenParty{
+			{Type: "company"},
+			{Type: "person"},
+			{Type: "company", TaxID: taxID},
+			{Type: "company", TaxID: "abc"},
+		},
+	}
+	v = Struct(o)
+	v.StopOnError = false
+	is.False(v.Validate())
+	is.Equal([]string{"Items.0.TaxID", "Items.3.TaxID"}, v.FieldErrors().Fields())
+
+	// concurrent validating
+	v = Struct(o)
+	v.StopOnError = false
+	v.Concurrency = 4
+	is.False(v.Validate())
+	is.Equal([]string{"Items.0.TaxID", "Items.3.TaxID"}, v.FieldErrors().Fields())
+
+	o.Items = []whenParty{{Type: "person"}, {Type: "company", TaxID: taxID}}
+	is.True(Struct(o).Validate())
+
+	// the schema export keeps them as the conditional rules
+	doc, err := StructJSONSchema(&whenOrder{})
+	is.NoError(err)
+	is.Contains(jsonString(doc), `"x-validate-when"`)
+}
+
+func TestParseCondition(t *testing.T) {
+	is := assert.New(t)
+	v := Map(M{"type": "company", "num": 2, "empty": ""})
+
+	tests := map[string]bool{
+		"type":                true,
+		"!type":               false,
+		"empty":               false,
+		"!empty":              true,
+		"!notExist":           true,
+		"type=company":        true,
+		"type = person":       false,
+		"type=person,company": true,
+		"type!=company":       false,
+		"notExist!=abc":       true,
+		"num=1,2":             true,
+	}
+
+	for expr, want := range tests {
+		fn, err := parseCondition(expr)
+		is.NoError(err, expr)
+		is.Equal(want, fn(v), expr)
+	}
+
+	for _, expr := range []string{"", "=abc", "!", "a b=c"} {
+		_, err := parseCondition(expr)
+		is.Error(err, expr)
+	}
+}
@@ -0,0 +1,251 @@
+package validate
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"reflect"
+	"strings"
+)
+
+/*************************************************************
+ * CSV Data
+ *************************************************************/
+
+// CSVData is a data source of a CSV record, the header names are mapped to the fields.
+// it can be reused for the next record by SetRecord(), avoid the allocation for each row.
+//
+// the errors position is the line and column of the record: Pos.Line is the line number of the record start,
+// Pos.Column is the column number, both start from 1. Pos.Offset is -1.
+type CSVData struct {
+	// Types the column types for convert the string values. eg: {"age": reflect.Int}
+	// the values are converted by the convertType(), keep the string on convert failed.
+	Types map[string]reflect.Kind
+	// header name to the column index
+	index map[string]int
+	// current record and the line number of it
+	record []string
+	line   int
+	// the values set by the filters, default values.
+	values map[string]interface{}
+}
+
+// FromCSVRecord build data instance from the CSV header and record.
+//
+// Usage:
+// 	d := validate.FromCSVRecord([]string{"name", "age"}, []string{"inhere", "20"})
+// 	d.Types = map[string]reflect.Kind{"age": reflect.Int}
+// 	v := d.Create()
+func FromCSVRecord(header, record []string) *CSVData {
+	d := &CSVData{
+		index:  make(map[string]int, len(header)),
+		values: make(map[string]interface{}),
+	}
+
+	for i, name := range header {
+		d.index[name] = i
+	}
+
+	d.SetRecord(1, record)
+	return d
+}
+
+// SetRecord set the next record and the line number of it. the set values are cleared.
+func (d *CSVData) SetRecord(line int, record []string) {
+	d.line = line
+	d.record = record
+	for key := range d.values {
+		delete(d.values, key)
+	}
+}
+
+// Type get
+func (d *CSVData) Type() uint8 {
+	return uint8(sourceMap)
+}
+
+// Get value by the header name. the value is converted if the column type is set.
+func (d *CSVData) Get(field string) (interface{}, bool) {
+	if val, ok := d.values[field]; ok {
+		return val, true
+	}
+
+	idx, ok := d.index[field]
+	if !ok || idx >= len(d.record) {
+		return nil, false
+	}
+
+	val := d.record[idx]
+	if kind, ok := d.Types[field]; ok && val != "" {
+		if nVal, err := convertType(val, stringKind, kind); err == nil {
+			return nVal, true
+		}
+	}
+	return val, true
+}
+
+// Set value by key
+func (d *CSVData) Set(field string, val interface{}) (interface{}, error) {
+	d.values[field] = val
+	return val, nil
+}
+
+// Position get the line and column of the field. see CSVData
+func (d *CSVData) Position(field string) (Position, bool) {
+	// the column is 0 if the field is not a column
+	col := 0
+	if idx, ok := d.index[field]; ok {
+		col = idx + 1
+	}
+	return Position{Offset: -1, Line: d.line, Column: col}, true
+}
+
+// Create a Validation from data
+func (d *CSVData) Create(err ...error) *Validation {
+	return d.Validation(err...)
+}
+
+// Validation create from data
+func (d *CSVData) Validation(err ...error) *Validation {
+	if len(err) > 0 {
+		return NewValidation(d).WithError(err[0])
+	}
+	return NewValidation(d)
+}
+
+/*************************************************************
+ * CSV validate
+ *************************************************************/
+
+// CSVOption for the Schema.ValidateCSV()
+type CSVOption struct {
+	StreamOption
+	// Comma the field delimiter. default is ','
+	Comma rune
+	// Header the field names of the columns. if it is empty, the first row is the header.
+	Header []string
+	// Types the column types for convert the string values. see CSVData.Types
+	Types map[string]reflect.Kind
+}
+
+// ValidateCSV validate each record of the CSV by the schema rules. the records are read one by one,
+// a CSVData and Validation is reused for all rows, so the large file is fast and the memory is bounded.
+//
+// the RecordError.Index is the index of the record, the line and column are in the FieldError.Pos.
+// the line is the physical line of the record start, the quoted field can contain the new lines.
+// NOTICE: the Result of the StreamOption.OnRecord is reused for the next record, dont keep it after the callback.
+// the res.FieldErrors() can be kept.
+//
+// Usage:
+// 	sr, err := s.ValidateCSV(file, func(opt *validate.CSVOption) {
+// 		opt.Types = map[string]reflect.Kind{"age": reflect.Int}
+// 	})
+// 	for _, re := range sr.Errors {
+// 		for _, fe := range re.Errors {
+// 			fmt.Printf("line %d, column %d: %s\n", fe.Pos.Line, fe.Pos.Column, fe.Message)
+// 		}
+// 	}
+func (s *Schema) ValidateCSV(r io.Reader, fns ...func(opt *CSVOption)) (*StreamResult, error) {
+	opt := &CSVOption{Comma: ','}
+	opt.MaxErrors = DefaultStreamMaxErrors
+	for _, fn := range fns {
+		fn(opt)
+	}
+
+	lr := &csvLineReader{br: bufio.NewReader(r)}
+	cr := csv.NewReader(lr)
+	cr.Comma = opt.Comma
+	// allow the missing columns, they are not exists on validating.
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	header := opt.Header
+	if len(header) == 0 {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return &StreamResult{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		header = make([]string, len(record))
+		for i, name := range record {
+			// trim the UTF-8 BOM of the Excel exported file
+			header[i] = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		}
+	}
+
+	d := FromCSVRecord(header, nil)
+	d.Types = opt.Types
+	v := s.newValidation(d)
+
+	sr := &StreamResult{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return sr, nil
+		}
+		if err != nil {
+			return sr, err
+		}
+
+		d.SetRecord(lr.recordLine(record), record)
+		v.clearResult()
+		for _, fe := range s.ruleErrors {
+			v.AddFieldError(fe)
+		}
+
+		v.Validate(opt.Scene)
+		if sr.addRecord(&opt.StreamOption, v) {
+			return sr, nil
+		}
+	}
+}
+
+// csvLineReader gives the CSV reader at most one line on each Read, the csv.Reader
+// will not read ahead, so the line number of the record can be known after it is read.
+// NOTICE: the csv.Reader.FieldPos() can't be used, it is added in the go 1.17
+type csvLineReader struct {
+	br *bufio.Reader
+	// the rest bytes of the current line
+	buf []byte
+	// the number of the '\n' have been read
+	lines int
+	last  byte
+}
+
+// Read implements the io.Reader
+func (r *csvLineReader) Read(p []byte) (int, error) {
+	if len(r.buf) == 0 {
+		line, err := r.br.ReadSlice('\n')
+		if len(line) == 0 {
+			return 0, err
+		}
+		r.buf = line
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	if n > 0 {
+		r.last = p[n-1]
+		if r.last == '\n' {
+			r.lines++
+		}
+	}
+	return n, nil
+}
+
+// recordLine get the start line of the record has been read.
+// the new lines in the quoted fields are counted back from the end line.
+func (r *csvLineReader) recordLine(record []string) int {
+	line := r.lines
+	if r.last != '\n' {
+		line++ // the last line has no '\n'
+	}
+
+	for _, field := range record {
+		line -= strings.Count(field, "\n")
+	}
+	return line
+}
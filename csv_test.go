@@ -0,0 +1,143 @@
+package validate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVData(t *testing.T) {
+	is := assert.New(t)
+
+	d := FromCSVRecord([]string{"name", "age", "price", "vip"}, []string{"inhere", "20", "1.5", "true"})
+	d.Types = map[string]reflect.Kind{"age": reflect.Int, "price": reflect.Float64, "vip": reflect.Bool}
+
+	val, ok := d.Get("name")
+	is.True(ok)
+	is.Equal("inhere", val)
+	val, _ = d.Get("age")
+	is.Equal(20, val)
+	val, _ = d.Get("price")
+	is.Equal(1.5, val)
+	val, _ = d.Get("vip")
+	is.Equal(true, val)
+	_, ok = d.Get("not-exists")
+	is.False(ok)
+
+	pos, _ := d.Position("price")
+	is.Equal(Position{Offset: -1, Line: 1, Column: 3}, pos)
+
+	v := d.Create()
+	v.StringRules(MS{"age": "required|int|between:1,120", "status": "default:on|in:on,off"})
+	is.True(v.Validate())
+	is.Equal("on", v.SafeVal("status"))
+
+	// next record, the convert failed value is kept.
+	d.SetRecord(2, []string{"tom", "abc"})
+	val, _ = d.Get("age")
+	is.Equal("abc", val)
+	_, ok = d.Get("price")
+	is.False(ok)
+	_, ok = d.Get("status")
+	is.False(ok)
+}
+
+func TestSchema_ValidateCSV(t *testing.T) {
+	is := assert.New(t)
+
+	s := NewSchema(func(v *Validation) {
+		v.StopOnError = false
+		v.StringRules(MS{
+			"name":  "required|minLen:2",
+			"age":   "required|int|between:1,120",
+			"email": "email",
+		})
+	})
+
+	src := "\ufeffname, age ,email\n" +
+		"inhere,20,in@example.com\n" +
+		"t,200,abc\n" +
+		"tom,,\n" +
+		"jerry\n"
+
+	opt := func(opt *CSVOption) {
+		opt.Types = map[string]reflect.Kind{"age": reflect.Int}
+	}
+
+	sr, err := s.ValidateCSV(strings.NewReader(src), opt)
+	is.NoError(err)
+	is.Equal(4, sr.Total)
+	is.Equal(3, sr.Failed)
+	is.Len(sr.Errors, 3)
+
+	re := sr.Errors[0]
+	is.Equal(1, re.Index)
+	is.Equal([]string{"age", "email", "name"}, sortedFields(re.Errors))
+	for _, fe := range re.Errors {
+		is.Equal(3, fe.Pos.Line)
+	}
+
+	fe := re.Errors[0]
+	is.Equal("age", fe.Field)
+	is.Equal(2, fe.Pos.Column)
+	is.Equal(200, fe.Value)
+
+	is.Equal(4, sr.Errors[1].Errors[0].Pos.Line)
+	is.Equal("age", sr.Errors[1].Errors[0].Field)
+	is.Equal(5, sr.Errors[2].Errors[0].Pos.Line)
+
+	// no header row, the other delimiter
+	sr, err = s.ValidateCSV(strings.NewReader("inhere;20\nt;20\n"), func(opt *CSVOption) {
+		opt.Comma = ';'
+		opt.Header = []string{"name", "age"}
+		opt.Types = map[string]reflect.Kind{"age": reflect.Int}
+		opt.MaxFailures = 1
+	})
+	is.NoError(err)
+	is.Equal(2, sr.Total)
+	is.Equal(2, sr.Errors[0].Errors[0].Pos.Line)
+
+	// the quoted fields contain the new lines, the blank lines are skipped
+	src = "name,age,email\r\n" +
+		"\"in\r\nhere\",20,\r\n" +
+		"\n" +
+		"t,20,\"a\n\nb\"\n" +
+		"tom,0,\n" +
+		"\"j\nerry\",0,"
+	sr, err = s.ValidateCSV(strings.NewReader(src), opt)
+	is.NoError(err)
+	is.Equal(4, sr.Total)
+	is.Len(sr.Errors, 3)
+	is.Equal(5, sr.Errors[0].Errors[0].Pos.Line)
+	is.Equal(8, sr.Errors[1].Errors[0].Pos.Line)
+	is.Equal(9, sr.Errors[2].Errors[0].Pos.Line)
+
+	// empty
+	sr, err = s.ValidateCSV(strings.NewReader(""))
+	is.NoError(err)
+	is.True(sr.IsOK())
+
+	// parse error
+	_, err = s.ValidateCSV(strings.NewReader("name,age\n\"inhere,20\n"))
+	is.Error(err)
+
+	// the result maps are cleared for each record
+	var fields [][]string
+	sr, err = s.ValidateCSV(strings.NewReader("name,age,email\nt,200,abc\ninhere,20,\ntom,0,\n"), func(opt *CSVOption) {
+		opt.Types = map[string]reflect.Kind{"age": reflect.Int}
+		opt.OnRecord = func(index int, res *Result) bool {
+			fields = append(fields, sortedFields(res.FieldErrors()))
+			if index == 1 {
+				is.True(res.IsOK())
+				is.Empty(res.Errors)
+				is.Equal("inhere", res.SafeVal("name"))
+			}
+			return true
+		}
+	})
+	is.NoError(err)
+	is.Equal(2, sr.Failed)
+	is.Equal([][]string{{"age", "email", "name"}, nil, {"age"}}, fields)
+}
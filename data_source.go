@@ -1,6 +1,7 @@
 package validate
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,8 +10,10 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gookit/filter"
@@ -105,7 +108,7 @@ func (d *MapData) Get(field string) (interface{}, bool) {
 	// 	return fv, true
 	// }
 
-	return filter.GetByPath(field, d.Map)
+	return getByPath(field, d.Map)
 }
 
 // Create a Validation from data
@@ -186,6 +189,8 @@ type StructData struct {
 	fieldValues map[string]reflect.Value
 	// TODO field reflect values cache
 	fieldRftValues map[string]interface{}
+	// lock for the fields cache and set value, the data maybe validated concurrently.
+	mu sync.Mutex
 	// FieldTag name in the struct tags. for define filed translate
 	FieldTag string
 	// MessageTag define error message for the field.
@@ -207,6 +212,8 @@ var (
 	cmFaceType = reflect.TypeOf(new(CustomMessagesFace)).Elem()
 	ftFaceType = reflect.TypeOf(new(FieldTranslatorFace)).Elem()
 	cvFaceType = reflect.TypeOf(new(ConfigValidationFace)).Elem()
+	// context.Context type, use for check the validator func.
+	ctxType = reflect.TypeOf(new(context.Context)).Elem()
 )
 
 // Type get
@@ -221,10 +228,15 @@ func (d *StructData) Validation(err ...error) *Validation {
 
 // Validation create from the StructData
 func (d *StructData) Create(err ...error) *Validation {
-	v := NewValidation(d)
 	if len(err) > 0 && err[0] != nil {
-		return v.WithError(err[0])
+		return NewValidation(d).WithError(err[0])
 	}
+	return d.create(defRegistry)
+}
+
+// create Validation use the registry, and collect rules from the struct.
+func (d *StructData) create(reg *Registry) *Validation {
+	v := newValidationWith(reg, d)
 
 	// collect field filter/validate rules from struct tags
 	d.parseRulesFromTag(v)
@@ -256,33 +268,38 @@ func (d *StructData) Create(err ...error) *Validation {
 }
 
 // parse and collect rules from struct tags.
+// the parsed tags info will be cached by the struct type, see factory.structOf()
 func (d *StructData) parseRulesFromTag(v *Validation) {
 	var recursiveFunc func(vv reflect.Value, vt reflect.Type, preStrName string, parentIsAnonymous bool)
+	opt := Option()
 	if d.ValidateTag == "" {
-		d.ValidateTag = gOpt.ValidateTag
+		d.ValidateTag = opt.ValidateTag
 	}
 
 	if d.FilterTag == "" {
-		d.FilterTag = gOpt.FilterTag
+		d.FilterTag = opt.FilterTag
 	}
 
 	fMap := make(map[string]string, 0)
+	key := structKey{
+		validateTag: d.ValidateTag,
+		filterTag:   d.FilterTag,
+		fieldTag:    opt.FieldTag,
+		messageTag:  opt.MessageTag,
+		keyTag:      opt.KeyTag,
+	}
 
 	vv := d.value
 	vt := d.valueTpy
 	recursiveFunc = func(vv reflect.Value, vt reflect.Type, preStrName string, parentIsAnonymous bool) {
-		for i := 0; i < vt.NumField(); i++ {
-			fValue := removeValuePtr(vv).Field(i)
-			fv := vt.Field(i)
-			ft := vt.Field(i).Type
-			ft = removeTypePtr(ft)
-
-			// skip don't exported field
-			name := fv.Name
-			if name[0] >= 'a' && name[0] <= 'z' {
-				continue
-			}
+		key.typ = vt
+		cs := v.reg.structs.structOf(key)
+
+		for _, cf := range cs.fields {
+			fValue := removeValuePtr(vv).Field(cf.index)
+			ft := cf.typ
 
+			name := cf.name
 			if preStrName == "" {
 				d.fieldNames[name] = fieldAtTopStruct
 			} else {
@@ -294,37 +311,10 @@ func (d *StructData) parseRulesFromTag(v *Validation) {
 				}
 			}
 
-			// validate rule
-			vRule := fv.Tag.Get(d.ValidateTag)
-			if vRule != "" {
-				v.StringRule(name, vRule)
-			}
-
-			// filter rule
-			fRule := fv.Tag.Get(d.FilterTag)
-			if fRule != "" {
-				v.FilterRule(name, fRule)
-			}
-
-			// load field translate name. eg: `json:"user_name"`
-			if gOpt.FieldTag != "" {
-				fName := fv.Tag.Get(gOpt.FieldTag)
-				if fName != "" {
-					fMap[name] = fName
-				}
-			}
-
-			// load custom error messages.
-			// eg: `message:"required:name is required|minLen:name min len is %d"`
-			if gOpt.MessageTag != "" {
-				errMsg := fv.Tag.Get(gOpt.MessageTag)
-				if errMsg != "" {
-					d.loadMessagesFromTag(v.trans, name, vRule, errMsg)
-				}
-			}
+			// add validate/filter rules, translate name and messages for the field
+			cf.addTo(v, name, fMap)
 
 			// collect rules from sub-struct and from arrays/slices elements
-			// TODO should use ft == timeType check time.Time
 			if ft != timeType {
 				if fValue.Type().Kind() == reflect.Ptr && fValue.IsNil() {
 					continue
@@ -332,48 +322,14 @@ func (d *StructData) parseRulesFromTag(v *Validation) {
 
 				switch ft.Kind() {
 				case reflect.Struct:
-					recursiveFunc(fValue, ft, name, fv.Anonymous)
+					recursiveFunc(fValue, ft, name, cf.anonymous)
 
-				case reflect.Array, reflect.Slice:
-					fValue = removeValuePtr(fValue)
-					for j := 0; j < fValue.Len(); j++ {
-						elemValue := removeValuePtr(fValue.Index(j))
-						elemType := removeTypePtr(elemValue.Type())
-
-						arrayName := fmt.Sprintf("%s.%d", name, j)
-						if elemType.Kind() == reflect.Struct {
-							recursiveFunc(elemValue, elemType, arrayName, fv.Anonymous)
-						}
+				case reflect.Array, reflect.Slice, reflect.Map:
+					// add wildcard rules for the struct elements. eg: "Items.*.Name"
+					// will expand to the real element paths on validating.
+					if et := removeTypePtr(ft.Elem()); et != timeType && et.Kind() == reflect.Struct {
+						collectTypeRules(v, key, et, name+".*", fMap, map[reflect.Type]bool{vt: true})
 					}
-
-				case reflect.Map:
-					fValue = removeValuePtr(fValue)
-					for _, key := range fValue.MapKeys() {
-						key = removeValuePtr(key)
-						elemValue := removeValuePtr(fValue.MapIndex(key))
-						elemType := removeTypePtr(elemValue.Type())
-
-						kind := key.Kind()
-						format := "%s."
-						val := key.Interface()
-						switch {
-						case kind == reflect.String:
-							format += "%s"
-							val = strings.ReplaceAll(val.(string), "\"", "")
-						case kind >= reflect.Int && kind <= reflect.Uint64:
-							format += "%d"
-						case kind >= reflect.Float32 && kind <= reflect.Complex128:
-							format += "%f"
-						default:
-							format += "%#v"
-						}
-
-						arrayName := fmt.Sprintf(format, name, val)
-						if elemType.Kind() == reflect.Struct {
-							recursiveFunc(elemValue, elemType, arrayName, fv.Anonymous)
-						}
-					}
-
 				}
 			}
 		}
@@ -386,63 +342,37 @@ func (d *StructData) parseRulesFromTag(v *Validation) {
 	}
 }
 
-// eg: `message:"required:name is required|minLen:name min len is %d"`
-func (d *StructData) loadMessagesFromTag(trans *Translator, field, vRule, vMsg string) {
-	var msgKey, vName string
-
-	// only one message, use for first validator.
-	// eg: `message:"name is required"`
-	if !strings.ContainsRune(vMsg, '|') {
-		// eg: `message:"required:name is required"`
-		if strings.ContainsRune(vMsg, ':') {
-			nodes := strings.SplitN(vMsg, ":", 2)
-			vName = strings.TrimSpace(nodes[0])
-			// first is validator name
-			vMsg = strings.TrimSpace(nodes[1])
-		}
-
-		if vName == "" {
-			// eg `validate:"required|date"`
-			vName = vRule
-			if strings.ContainsRune(vRule, '|') {
-				nodes := strings.SplitN(vRule, "|", 2)
-				// use first validator name
-				vName = nodes[0]
-			}
-
-			// has params for validator: "minLen:5"
-			if strings.ContainsRune(vName, ':') {
-				nodes := strings.SplitN(vRule, ":", 2)
-				// use first validator name
-				vName = nodes[0]
-			}
-		}
-
-		// if rName, has := validatorAliases[validator]; has {
-		// 	msgKey = field + "." + rName
-		// } else {
-		msgKey = field + "." + vName
-		// }
-
-		trans.AddMessage(msgKey, vMsg)
+// collect rules by the struct type. the sub-struct, struct pointer and
+// the struct elements of slice/array/map will be collected too.
+func collectTypeRules(v *Validation, key structKey, vt reflect.Type, preStrName string, fMap map[string]string, visited map[reflect.Type]bool) {
+	// fix: recursive struct type. eg: type Node struct { Next *Node }
+	if visited[vt] {
 		return
 	}
 
-	// multi message for validators
-	// eg: `message:"required:name is required | minLen:name min len is %d"`
-	msgNodes := strings.Split(vMsg, "|")
-	for _, validatorWithMsg := range msgNodes {
-		// validatorWithMsg eg: "required:name is required"
-		nodes := strings.SplitN(validatorWithMsg, ":", 2)
+	visited[vt] = true
+	defer delete(visited, vt)
+
+	key.typ = vt
+	for _, cf := range v.reg.structs.structOf(key).fields {
+		name := cf.name
+		if preStrName != "" {
+			name = preStrName + "." + name
+		}
 
-		validator := nodes[0]
-		if rName, has := validatorAliases[validator]; has {
-			msgKey = field + "." + rName
-		} else {
-			msgKey = field + "." + validator
+		cf.addTo(v, name, fMap)
+		if cf.typ == timeType {
+			continue
 		}
 
-		trans.AddMessage(msgKey, strings.TrimSpace(nodes[1]))
+		switch cf.typ.Kind() {
+		case reflect.Struct:
+			collectTypeRules(v, key, cf.typ, name, fMap, visited)
+		case reflect.Array, reflect.Slice, reflect.Map:
+			if et := removeTypePtr(cf.typ.Elem()); et != timeType && et.Kind() == reflect.Struct {
+				collectTypeRules(v, key, et, name+".*", fMap, visited)
+			}
+		}
 	}
 }
 
@@ -452,6 +382,12 @@ func (d *StructData) loadMessagesFromTag(trans *Translator, field, vRule, vMsg s
 
 // Get value by field name
 func (d *StructData) Get(field string) (interface{}, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.get(field)
+}
+
+func (d *StructData) get(field string) (interface{}, bool) {
 	var fv reflect.Value
 	field = strutil.UpperFirst(field)
 
@@ -484,15 +420,11 @@ func (d *StructData) Get(field string) (interface{}, bool) {
 		for i, fieldNode := range fieldNodes {
 			fieldNode = strings.ReplaceAll(fieldNode, "\"", "") // for strings as keys
 
-			kind := fv.Type().Kind()
-			switch kind {
-			case reflect.Array, reflect.Slice:
-				index, _ := strconv.Atoi(fieldNode)
-				fv = fv.Index(index)
-			case reflect.Map:
-				fv = fv.MapIndex(reflect.ValueOf(fieldNode))
-			default:
+			if fv.Kind() == reflect.Struct {
 				fv = fv.FieldByName(fieldNode)
+			} else {
+				// slice index or map key
+				fv = indirectInterface(elemValueByKey(fv, fieldNode))
 			}
 
 			fv = removeValuePtr(fv)
@@ -505,7 +437,7 @@ func (d *StructData) Get(field string) (interface{}, bool) {
 				return nil, false
 			}
 
-			if i < lastIndex && fv.Type().Kind() != reflect.Struct {
+			if i < lastIndex && !isContainerKind(fv.Kind()) {
 				return nil, false
 			}
 		}
@@ -546,8 +478,11 @@ func (d *StructData) Get(field string) (interface{}, bool) {
 // Set value by field name.
 // Notice: `StructData.src` the incoming struct must be a pointer to set the value
 func (d *StructData) Set(field string, val interface{}) (newVal interface{}, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	field = strutil.UpperFirst(field)
-	if !d.HasField(field) { // field not found
+	if !d.hasField(field) { // field not found
 		return nil, ErrNoField
 	}
 
@@ -625,6 +560,12 @@ func (d *StructData) FuncValue(name string) (reflect.Value, bool) {
 
 // HasField in the src struct
 func (d *StructData) HasField(field string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.hasField(field)
+}
+
+func (d *StructData) hasField(field string) bool {
 	if _, ok := d.fieldNames[field]; ok {
 		return true
 	}
@@ -762,9 +703,68 @@ func (d FormData) Get(key string) (interface{}, bool) {
 		return fh, true
 	}
 
+	// get one of multi values by index. eg: "tags.1"
+	if pos := strings.LastIndexByte(key, '.'); pos > 0 {
+		if vs, ok := d.Form[key[:pos]]; ok {
+			index, err := strconv.Atoi(key[pos+1:])
+			if err == nil && index >= 0 && index < len(vs) {
+				return vs[index], true
+			}
+		}
+	}
+
 	return nil, false
 }
 
+// list element keys of the field, use for expand wildcard path.
+// eg: "tags" with multi values -> "0", "1"; "items" -> "0", "1" by keys "items.0.name", "items.1.name"
+func (d FormData) elemKeys(field string) (keys []string) {
+	if vs, ok := d.Form[field]; ok {
+		keys = make([]string, len(vs))
+		for i := range vs {
+			keys[i] = strconv.Itoa(i)
+		}
+		return
+	}
+
+	prefix := field + "."
+	exists := make(map[string]bool)
+	for key := range d.Form {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		sub := key[len(prefix):]
+		if pos := strings.IndexByte(sub, '.'); pos > -1 {
+			sub = sub[:pos]
+		}
+
+		if sub != "" && !exists[sub] {
+			exists[sub] = true
+			keys = append(keys, sub)
+		}
+	}
+
+	sortElemKeys(keys)
+	return
+}
+
+// sortElemKeys sort the index keys by number, eg: "2" is before "10".
+// the other keys are after the index keys, and sorted by string.
+func sortElemKeys(keys []string) {
+	sort.Slice(keys, func(i, j int) bool {
+		ni, errI := strconv.Atoi(keys[i])
+		nj, errJ := strconv.Atoi(keys[j])
+		if errI == nil && errJ == nil {
+			return ni < nj
+		}
+		if errI == nil || errJ == nil {
+			return errI == nil
+		}
+		return keys[i] < keys[j]
+	})
+}
+
 // String value get by key
 func (d FormData) String(key string) string {
 	return d.Form.Get(key)
@@ -0,0 +1,183 @@
+package validate
+
+import (
+	"os"
+	"reflect"
+	"strings"
+)
+
+/*************************************************************
+ * Env Data
+ *************************************************************/
+
+// dataPreparer the data source can prepare itself by the rules before validating.
+type dataPreparer interface {
+	prepare(v *Validation)
+}
+
+// fieldNamer the data source provides the display name of the field in the error messages.
+type fieldNamer interface {
+	fieldName(field string) string
+}
+
+// the field types for convert the env values, by the type validator of the rules.
+var envRuleKinds = map[string]reflect.Kind{
+	"isInt":   reflect.Int,
+	"isUint":  reflect.Int,
+	"isFloat": reflect.Float64,
+	"isBool":  reflect.Bool,
+}
+
+// replace the field path separators to "_" for the env name.
+var envNameReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// EnvData is a data source of the environment variables with a prefix.
+// the field path is mapped to the env name: "db.port" -> "APP_DB_PORT", "db.max_conn" -> "APP_DB_MAX_CONN".
+//
+// the values are converted by the type validator of the field(int, uint, float, bool),
+// so the rules like "int|between:1,65535" can be used for the env values.
+// the env name is used as the field name in the error messages, unless it has been translated.
+//
+// Usage:
+// 	v := validate.FromEnv("APP").Create()
+// 	v.StringRules(validate.MS{
+// 		"db.host": "required",
+// 		"db.port": "default:5432|int|between:1,65535",
+// 	})
+// 	if !v.Validate() {
+// 		fmt.Println(v.Errors) // eg: "APP_DB_PORT value must be in the range 1 - 65535"
+// 	}
+// 	port := v.SafeVal("db.port")
+type EnvData struct {
+	// Prefix of the env names, without the "_". eg: "APP"
+	Prefix string
+	// the env variables snapshot, only contains the names with the prefix.
+	vars map[string]string
+	// the field types collected from the rules
+	kinds map[string]reflect.Kind
+	// the values set by the filters, default values.
+	values map[string]interface{}
+}
+
+// FromEnv build data instance from the environment variables with the prefix.
+// the variables are read on created, the later changes are not visible.
+func FromEnv(prefix string) *EnvData {
+	d := &EnvData{
+		Prefix: strings.TrimSuffix(prefix, "_"),
+		vars:   make(map[string]string),
+		kinds:  make(map[string]reflect.Kind),
+		values: make(map[string]interface{}),
+	}
+
+	namePrefix := d.EnvName("")
+	for _, kv := range os.Environ() {
+		idx := strings.IndexByte(kv, '=')
+		if idx > 0 && strings.HasPrefix(kv[:idx], namePrefix) {
+			d.vars[kv[:idx]] = kv[idx+1:]
+		}
+	}
+	return d
+}
+
+// EnvName get the env name of the field. eg: "db.port" -> "APP_DB_PORT"
+func (d *EnvData) EnvName(field string) string {
+	name := strings.ToUpper(envNameReplacer.Replace(field))
+	if d.Prefix == "" {
+		return name
+	}
+	return d.Prefix + "_" + name
+}
+
+// Type get
+func (d *EnvData) Type() uint8 {
+	return uint8(sourceMap)
+}
+
+// Get value by the field path. the value is converted if the field has a type rule.
+func (d *EnvData) Get(field string) (interface{}, bool) {
+	if val, ok := d.values[field]; ok {
+		return val, true
+	}
+
+	val, ok := d.vars[d.EnvName(field)]
+	if !ok {
+		return nil, false
+	}
+
+	if kind, ok := d.kinds[field]; ok && val != "" {
+		if nVal, err := convertType(val, stringKind, kind); err == nil {
+			return nVal, true
+		}
+	}
+	return val, true
+}
+
+// Set value by key
+func (d *EnvData) Set(field string, val interface{}) (interface{}, error) {
+	d.values[field] = val
+	return val, nil
+}
+
+// Create a Validation from data
+func (d *EnvData) Create(err ...error) *Validation {
+	return d.Validation(err...)
+}
+
+// Validation create from data
+func (d *EnvData) Validation(err ...error) *Validation {
+	if len(err) > 0 {
+		return NewValidation(d).WithError(err[0])
+	}
+	return NewValidation(d)
+}
+
+// prepare collect the field types from the rules.
+// NOTICE: the translator maybe shared by the Schema, dont add the env names to it.
+func (d *EnvData) prepare(v *Validation) {
+	// the kinds of the last validating are not used
+	d.kinds = make(map[string]reflect.Kind)
+	defValues := make(map[string]interface{})
+	for _, rule := range v.rules {
+		if kind, ok := envRuleKinds[rule.realName]; ok {
+			for _, field := range rule.fields {
+				d.kinds[field] = kind
+				if defVal, ok := d.convertDefValue(v, field, kind); ok {
+					defValues[field] = defVal
+				}
+			}
+		}
+	}
+
+	if len(defValues) == 0 {
+		return
+	}
+
+	// the default values maybe shared by the Schema, replace them by a copy.
+	for field, val := range v.defValues {
+		if _, ok := defValues[field]; !ok {
+			defValues[field] = val
+		}
+	}
+	v.defValues = defValues
+}
+
+// the env name is used as the field name in the error messages, unless it has been translated.
+func (d *EnvData) fieldName(field string) string {
+	return d.EnvName(field)
+}
+
+// the default value in the rule is string, use the converted value for the not exists env.
+// the converted value is used as the default value, it is validated only on the v.CheckDefault is true.
+func (d *EnvData) convertDefValue(v *Validation, field string, kind reflect.Kind) (interface{}, bool) {
+	defVal, ok := v.defValues[field].(string)
+	if !ok || defVal == "" {
+		return nil, false
+	}
+
+	if _, ok = d.vars[d.EnvName(field)]; ok {
+		return nil, false
+	}
+
+	nVal, err := convertType(defVal, stringKind, kind)
+	return nVal, err == nil
+}
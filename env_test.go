@@ -0,0 +1,121 @@
+package validate
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromEnv(t *testing.T) {
+	is := assert.New(t)
+
+	envs := map[string]string{
+		"VALIDATE_TEST_DB_HOST":     "localhost",
+		"VALIDATE_TEST_DB_PORT":     "3306",
+		"VALIDATE_TEST_DB_MAX_CONN": "abc",
+		"VALIDATE_TEST_DEBUG":       "true",
+	}
+	for name, val := range envs {
+		_ = os.Setenv(name, val)
+		defer os.Unsetenv(name)
+	}
+
+	d := FromEnv("VALIDATE_TEST_")
+	is.Equal("VALIDATE_TEST", d.Prefix)
+	is.Equal("VALIDATE_TEST_DB_MAX_CONN", d.EnvName("db.max_conn"))
+	is.Equal("VALIDATE_TEST_DB_MAX_CONN", d.EnvName("db.max-conn"))
+	is.Equal("PORT", FromEnv("").EnvName("port"))
+
+	val, ok := d.Get("db.host")
+	is.True(ok)
+	is.Equal("localhost", val)
+	// not changed after created
+	_ = os.Setenv("VALIDATE_TEST_DB_NAME", "test")
+	defer os.Unsetenv("VALIDATE_TEST_DB_NAME")
+	_, ok = d.Get("db.name")
+	is.False(ok)
+
+	v := d.Create()
+	v.StringRules(MS{
+		"db.host":    "required",
+		"db.port":    "required|int|between:1,65535",
+		"db.timeout": "default:30|int",
+		"debug":      "bool",
+	})
+	is.True(v.Validate())
+	is.Equal(3306, v.SafeVal("db.port"))
+	is.Equal(30, v.SafeVal("db.timeout"))
+	is.Equal(true, v.SafeVal("debug"))
+
+	// the default value is not validated on the CheckDefault is false
+	v = d.Create()
+	v.StringRules(MS{"db.timeout": "default:300|int|max:60"})
+	is.True(v.Validate())
+	is.Equal(300, v.SafeVal("db.timeout"))
+
+	v = d.Create()
+	v.CheckDefault = true
+	v.StringRules(MS{"db.timeout": "default:300|int|max:60"})
+	is.False(v.Validate())
+	is.Contains(v.Errors.FieldOne("db.timeout"), "VALIDATE_TEST_DB_TIMEOUT ")
+
+	// the error messages use the env name
+	v = FromEnv("VALIDATE_TEST").Create()
+	v.StopOnError = false
+	v.StringRules(MS{
+		"db.user":     "required",
+		"db.max_conn": "int",
+		"db.port":     "int|max:1024",
+	})
+	v.AddTranslates(MS{"db.user": "Database User"})
+	is.False(v.Validate())
+	is.Equal("Database User is required and not empty", v.Errors.FieldOne("db.user"))
+	is.Equal("VALIDATE_TEST_DB_MAX_CONN value must be an integer", v.Errors.FieldOne("db.max_conn"))
+	is.Contains(v.Errors.FieldOne("db.port"), "VALIDATE_TEST_DB_PORT ")
+
+	fe := v.FieldErrors()[0]
+	is.Equal("db.max_conn", fe.Field)
+	is.Equal("abc", fe.Value)
+}
+
+func TestFromEnv_schema(t *testing.T) {
+	is := assert.New(t)
+
+	envs := map[string]string{
+		"VALIDATE_AA_PORT": "abc",
+		"VALIDATE_BB_PORT": "70000",
+	}
+	for name, val := range envs {
+		_ = os.Setenv(name, val)
+		defer os.Unsetenv(name)
+	}
+
+	s := NewSchema(func(v *Validation) {
+		v.StringRules(MS{"port": "int|between:1,65535"})
+	})
+
+	res := s.Validate(FromEnv("VALIDATE_AA"), "")
+	is.Equal("VALIDATE_AA_PORT value must be an integer", res.Errors.One())
+	res = s.Validate(FromEnv("VALIDATE_BB"), "")
+	is.Equal("VALIDATE_BB_PORT value must be in the range 1 - 65535", res.Errors.One())
+	// the schema translator is not changed
+	is.Empty(s.trans.FieldMap())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		prefix := "VALIDATE_AA"
+		if i%2 == 1 {
+			prefix = "VALIDATE_BB"
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := s.Validate(FromEnv(prefix), "")
+			assert.Contains(t, res.Errors.One(), prefix+"_PORT ")
+		}()
+	}
+	wg.Wait()
+}
@@ -11,10 +11,7 @@ import (
  * Global filters
  *************************************************************/
 
-var (
-	filterValues map[string]reflect.Value
-	emptyValue   = reflect.Value{}
-)
+var emptyValue = reflect.Value{}
 
 // AddFilters add global filters
 func AddFilters(m map[string]interface{}) {
@@ -25,11 +22,7 @@ func AddFilters(m map[string]interface{}) {
 
 // AddFilter add global filter to the pkg.
 func AddFilter(name string, filterFunc interface{}) {
-	if filterValues == nil {
-		filterValues = make(map[string]reflect.Value)
-	}
-
-	filterValues[name] = checkFilterFunc(name, filterFunc)
+	defRegistry.AddFilter(name, filterFunc)
 }
 
 /*************************************************************
@@ -59,7 +52,7 @@ func (v *Validation) FilterFuncValue(name string) reflect.Value {
 		return fv
 	}
 
-	if fv, ok := filterValues[name]; ok {
+	if fv, ok := v.reg.filterValue(name); ok {
 		return fv
 	}
 
@@ -88,8 +81,9 @@ func (v *Validation) FilterRule(field string, rule string) *FilterRule {
 
 // FilterRules add multi filter rules.
 func (v *Validation) FilterRules(rules map[string]string) *Validation {
-	for field, rule := range rules {
-		v.FilterRule(field, rule)
+	// add by sorted field names, keep the rules order is deterministic.
+	for _, field := range MS(rules).sortedKeys() {
+		v.FilterRule(field, rules[field])
 	}
 	return v
 }
@@ -139,59 +133,75 @@ func (r *FilterRule) AddFilters(filters ...string) *FilterRule {
 func (r *FilterRule) Apply(v *Validation) (err error) {
 	// filter field value
 	for _, field := range r.Fields() {
-		// get field value.
-		val, has := v.Get(field)
-		if !has { // no field
-			defVal, ok := v.GetDefValue(field)
-			// there is also no custom default value
-			if !ok {
-				continue
-			}
-
-			// update source data field value
-			newVal, err := v.updateValue(field, defVal)
-			if err != nil {
-				return err
+		// wildcard field. eg: "items.*.name"
+		if strings.ContainsRune(field, '*') {
+			for _, path := range v.expandField(field) {
+				if err = r.applyField(v, path, field); err != nil {
+					return
+				}
 			}
+			continue
+		}
 
-			// re-set value
-			val = newVal
+		if err = r.applyField(v, field, field); err != nil {
+			return
+		}
+	}
+	return
+}
 
-			// dont need check default value
-			if !v.CheckDefault {
-				// save validated value.
-				v.safeData[field] = newVal
-				continue
-			}
+// apply filters to the field value. the pattern is the field name on add rule.
+func (r *FilterRule) applyField(v *Validation, field, pattern string) error {
+	// get field value.
+	val, has := v.Get(field)
+	if !has { // no field
+		defVal, ok := v.GetDefValue(pattern)
+		// there is also no custom default value
+		if !ok {
+			return nil
+		}
 
-			// go on check custom default value
-			has = true
+		// update source data field value
+		newVal, err := v.updateValue(field, defVal)
+		if err != nil {
+			return err
 		}
 
-		// call filters
-		for i, name := range r.filters {
-			fv := v.FilterFuncValue(name)
-			args := parseArgString(r.filterArgs[i])
-			if !fv.IsValid() { // is built int filters
-				val, err = filter.Apply(name, val, args)
-			} else {
-				val, err = callCustomFilter(fv, val, args)
-			}
-			if err != nil {
-				return err
-			}
+		// dont need check default value
+		if !v.CheckDefault {
+			// save validated value.
+			v.safeData[field] = newVal
+			return nil
 		}
 
-		// update source data field value
-		newVal, err := v.updateValue(field, val)
+		// go on check custom default value
+		val = newVal
+	}
+
+	// call filters
+	var err error
+	for i, name := range r.filters {
+		fv := v.FilterFuncValue(name)
+		args := parseArgString(r.filterArgs[i])
+		if !fv.IsValid() { // is built int filters
+			val, err = filter.Apply(name, val, args)
+		} else {
+			val, err = callCustomFilter(fv, val, args)
+		}
 		if err != nil {
 			return err
 		}
+	}
 
-		// save filtered value.
-		v.filteredData[field] = newVal
+	// update source data field value
+	newVal, err := v.updateValue(field, val)
+	if err != nil {
+		return err
 	}
-	return
+
+	// save filtered value.
+	v.filteredData[field] = newVal
+	return nil
 }
 
 // Fields name get
@@ -199,6 +209,17 @@ func (r *FilterRule) Fields() []string {
 	return r.fields
 }
 
+// clone a new filter rule
+func (r *FilterRule) clone() *FilterRule {
+	nr := newFilterRule(append([]string(nil), r.fields...))
+	nr.filters = append([]string(nil), r.filters...)
+
+	for i, args := range r.filterArgs {
+		nr.filterArgs[i] = args
+	}
+	return nr
+}
+
 func callCustomFilter(fv reflect.Value, val interface{}, args []string) (interface{}, error) {
 	var rs []reflect.Value
 	if len(args) > 0 {
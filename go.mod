@@ -6,4 +6,5 @@ require (
 	github.com/gookit/filter v1.1.2
 	github.com/gookit/goutil v0.3.14
 	github.com/stretchr/testify v1.7.0
+	gopkg.in/yaml.v3 v3.0.1
 )
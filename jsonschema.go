@@ -0,0 +1,1352 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/filter"
+)
+
+// JSON Schema export settings
+const (
+	// JSONSchemaDraft the dialect of the exported JSON Schema
+	JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+	// JSONSchemaExtKeyword the extension keyword for the rules cannot be expressed by JSON Schema.
+	// eg: {"x-validate": ["eqField:password", "isCnMobile"]}
+	JSONSchemaExtKeyword = "x-validate"
+	// JSONSchemaWhenKeyword the extension keyword for the conditional rules.
+	// eg: {"x-validate-when": ["required"]}
+	JSONSchemaWhenKeyword = "x-validate-when"
+	// JSONSchemaMessagesKeyword the extension keyword for the custom error messages of the message tag.
+	// eg: {"x-validate-messages": {"required": "name is required"}}
+	JSONSchemaMessagesKeyword = "x-validate-messages"
+)
+
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+/*************************************************************
+ * JSON Schema export
+ *************************************************************/
+
+// JSONSchema export the rules as a JSON Schema(draft 2020-12) document.
+//
+// the built-in validators are mapped to the native keywords. eg: "minLen:6" -> {"minLength": 6}.
+// the others are reported by the JSONSchemaExtKeyword, the scenes are ignored.
+//
+// Usage:
+// 	v := validate.New(data)
+// 	v.StringRules(validate.MS{"name": "required|minLen:6"})
+// 	bs, err := json.Marshal(v.JSONSchema())
+func (v *Validation) JSONSchema() M {
+	b := newJSONSchemaBuilder()
+	b.addRules(v.rules, v.defValues)
+	return b.build()
+}
+
+// JSONSchema export the schema rules as a JSON Schema(draft 2020-12) document.
+// see Validation.JSONSchema()
+func (s *Schema) JSONSchema() M {
+	b := newJSONSchemaBuilder()
+	b.addRules(s.rules, s.defValues)
+	return b.build()
+}
+
+// StructJSONSchema export the struct tag rules as a JSON Schema(draft 2020-12) document.
+// the field types are mapped to the "type" keyword, and the property name use the field tag(default: json).
+//
+// Usage:
+// 	doc, err := validate.StructJSONSchema(&UserForm{})
+func StructJSONSchema(s interface{}) (M, error) {
+	v, vt, err := structTypeValidation(s)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newJSONSchemaBuilder()
+	b.addStruct(b.root, "", vt, map[reflect.Type]bool{})
+	b.addRules(v.rules, v.defValues)
+	return b.build(), nil
+}
+
+// jsonSchemaNode is a schema node on building
+type jsonSchemaNode struct {
+	typ      string
+	keywords M
+	// property name to sub node, names keep the add order
+	props    map[string]*jsonSchemaNode
+	names    []string
+	required []string
+	// items of the array, or values of the map
+	items *jsonSchemaNode
+	// the map keys schema. "propertyNames"
+	keys *jsonSchemaNode
+	// min, max length. mapping to the keyword by the type on build.
+	minLen, maxLen interface{}
+	// the value must be not empty, by the "required" rule.
+	notEmpty bool
+	anyOf    []*jsonSchemaNode
+	// the keywords conflict with the exists keywords
+	allOf []M
+	// unsupported rules and conditional rules
+	ext, when []string
+}
+
+func newJSONSchemaNode() *jsonSchemaNode {
+	return &jsonSchemaNode{keywords: make(M)}
+}
+
+// set the keyword value, will add to allOf on the keyword has been set.
+// for the number bounds, the stricter one is kept.
+func (n *jsonSchemaNode) set(key string, val interface{}) {
+	old, ok := n.keywords[key]
+	if !ok {
+		n.keywords[key] = val
+		return
+	}
+
+	if reflect.DeepEqual(old, val) {
+		return
+	}
+
+	switch key {
+	case "minimum", "exclusiveMinimum", "maximum", "exclusiveMaximum":
+		if ret, ok := compareNumber(val, old); ok {
+			isMin := strings.HasSuffix(strings.ToLower(key), "minimum")
+			if (isMin && ret > 0) || (!isMin && ret < 0) {
+				n.keywords[key] = val
+			}
+			return
+		}
+	}
+	n.allOf = append(n.allOf, M{key: val})
+}
+
+func (n *jsonSchemaNode) setType(typ string) {
+	if n.typ == "" {
+		n.typ = typ
+	}
+}
+
+func (n *jsonSchemaNode) prop(name string) *jsonSchemaNode {
+	if c, ok := n.props[name]; ok {
+		return c
+	}
+
+	if n.props == nil {
+		n.props = make(map[string]*jsonSchemaNode)
+	}
+
+	c := newJSONSchemaNode()
+	n.props[name] = c
+	n.names = append(n.names, name)
+	return c
+}
+
+func (n *jsonSchemaNode) addRequired(name string) {
+	for _, s := range n.required {
+		if s == name {
+			return
+		}
+	}
+	n.required = append(n.required, name)
+}
+
+func (n *jsonSchemaNode) build() M {
+	m := make(M, len(n.keywords)+4)
+	for k, val := range n.keywords {
+		m[k] = val
+	}
+
+	if n.typ != "" {
+		m["type"] = n.typ
+	}
+
+	// the "required" rule also checks the value is not empty.
+	// the string and array are mapped to the min length, the others are kept in the extension.
+	minLen, ext := n.minLen, n.ext
+	if n.notEmpty {
+		if n.typ == "string" || n.typ == "array" {
+			if ln, ok := minLen.(int); !ok || ln < 1 {
+				minLen = 1
+			}
+		} else {
+			ext = append([]string{"required"}, ext...)
+		}
+	}
+
+	if minLen != nil || n.maxLen != nil {
+		minKey, maxKey := "minLength", "maxLength"
+		switch n.typ {
+		case "array":
+			minKey, maxKey = "minItems", "maxItems"
+		case "object":
+			minKey, maxKey = "minProperties", "maxProperties"
+		}
+
+		if minLen != nil {
+			m[minKey] = minLen
+		}
+		if n.maxLen != nil {
+			m[maxKey] = n.maxLen
+		}
+	}
+
+	// the enum values from string rule are strings. eg: "in:1,2"
+	if enum, ok := m["enum"].([]interface{}); ok && (n.typ == "integer" || n.typ == "number") {
+		m["enum"] = numericEnum(enum)
+	}
+
+	if len(n.props) > 0 {
+		props := make(M, len(n.props))
+		for _, name := range n.names {
+			props[name] = n.props[name].build()
+		}
+		m["properties"] = props
+	}
+
+	if len(n.required) > 0 {
+		m["required"] = n.required
+	}
+
+	if n.items != nil {
+		if n.typ == "object" {
+			m["additionalProperties"] = n.items.build()
+		} else {
+			m["items"] = n.items.build()
+		}
+	}
+
+	if n.keys != nil {
+		m["propertyNames"] = n.keys.build()
+	}
+
+	if len(n.anyOf) > 0 {
+		alts := make([]M, len(n.anyOf))
+		for i, alt := range n.anyOf {
+			alts[i] = alt.build()
+		}
+		m["anyOf"] = alts
+	}
+
+	if len(n.allOf) > 0 {
+		m["allOf"] = n.allOf
+	}
+
+	if len(ext) > 0 {
+		m[JSONSchemaExtKeyword] = ext
+	}
+	if len(n.when) > 0 {
+		m[JSONSchemaWhenKeyword] = n.when
+	}
+	return m
+}
+
+// jsonSchemaBuilder build the JSON Schema by the field paths and rules
+type jsonSchemaBuilder struct {
+	root *jsonSchemaNode
+	// field path to the node. eg: "users.*.name"
+	nodes map[string]*jsonSchemaNode
+	// field path to the property name in the parent node.
+	// eg: {"Users.*.Name": "name"}
+	names map[string]string
+	// the struct field paths are not in the JSON. eg: `json:"-"`
+	skips map[string]bool
+}
+
+func newJSONSchemaBuilder() *jsonSchemaBuilder {
+	root := newJSONSchemaNode()
+	root.typ = "object"
+	root.keywords["$schema"] = JSONSchemaDraft
+
+	return &jsonSchemaBuilder{
+		root:  root,
+		nodes: map[string]*jsonSchemaNode{"": root},
+		names: make(map[string]string),
+		skips: make(map[string]bool),
+	}
+}
+
+// check the field path is skipped, or it is in a skipped field.
+func (b *jsonSchemaBuilder) skipped(path string) bool {
+	for len(path) > 0 {
+		if b.skips[path] {
+			return true
+		}
+		path, _ = splitFieldPath(path)
+	}
+	return false
+}
+
+func (b *jsonSchemaBuilder) build() M {
+	return b.root.build()
+}
+
+// node get or create the node by field path. eg: "users.*.name", "tags.#key"
+func (b *jsonSchemaBuilder) node(path string) *jsonSchemaNode {
+	if n, ok := b.nodes[path]; ok {
+		return n
+	}
+
+	parentPath, name := splitFieldPath(path)
+	parent := b.node(parentPath)
+
+	var n *jsonSchemaNode
+	switch name {
+	case "*":
+		if parent.items == nil {
+			parent.items = newJSONSchemaNode()
+		}
+		n = parent.items
+		parent.setType("array")
+	case keyPathSuffix[1:]: // "#key"
+		if parent.keys == nil {
+			parent.keys = newJSONSchemaNode()
+		}
+		n = parent.keys
+		parent.setType("object")
+	default:
+		if pName, ok := b.names[path]; ok {
+			name = pName
+		}
+		n = parent.prop(name)
+		parent.setType("object")
+	}
+
+	b.nodes[path] = n
+	return n
+}
+
+// add the struct fields as properties of the node. the anonymous struct fields are inlined.
+func (b *jsonSchemaBuilder) addStruct(n *jsonSchemaNode, path string, vt reflect.Type, visited map[reflect.Type]bool) {
+	// recursive struct type, only set the type.
+	if visited[vt] {
+		return
+	}
+
+	visited[vt] = true
+	defer delete(visited, vt)
+
+	key := optionStructKey()
+	key.typ = vt
+	for _, cf := range defRegistry.structs.structOf(key).fields {
+		fPath := cf.name
+		if path != "" {
+			fPath = path + "." + cf.name
+		}
+
+		// skip the field like encoding/json. NOTICE: the name is "-" on the tag is "-,"
+		if cf.transName == "-" {
+			b.skips[fPath] = true
+			continue
+		}
+
+		name := strings.SplitN(cf.transName, ",", 2)[0]
+		if cf.anonymous && name == "" && cf.typ.Kind() == reflect.Struct {
+			b.nodes[fPath] = n
+			b.addStruct(n, fPath, cf.typ, visited)
+			continue
+		}
+
+		if name == "" {
+			name = cf.name
+		}
+
+		b.names[fPath] = name
+		c := n.prop(name)
+		b.nodes[fPath] = c
+		b.addType(c, fPath, cf.typ, visited)
+
+		// custom error messages. eg: `message:"required:name is required"`
+		if len(cf.messages) > 0 {
+			c.keywords[JSONSchemaMessagesKeyword] = cf.messages
+		}
+	}
+}
+
+// set the node type by the go type
+func (b *jsonSchemaBuilder) addType(n *jsonSchemaNode, path string, vt reflect.Type, visited map[reflect.Type]bool) {
+	vt = removeTypePtr(vt)
+	switch vt {
+	case timeType:
+		n.typ = "string"
+		n.keywords["format"] = "date-time"
+		return
+	case jsonNumberType:
+		n.typ = "number"
+		return
+	}
+
+	switch vt.Kind() {
+	case reflect.String:
+		n.typ = "string"
+	case reflect.Bool:
+		n.typ = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n.typ = "integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n.typ = "integer"
+		n.keywords["minimum"] = int64(0)
+	case reflect.Float32, reflect.Float64:
+		n.typ = "number"
+	case reflect.Slice, reflect.Array:
+		// []byte is encoded as base64 string
+		if vt.Elem().Kind() == reflect.Uint8 {
+			n.typ = "string"
+			return
+		}
+
+		n.typ = "array"
+		n.items = newJSONSchemaNode()
+		b.nodes[path+".*"] = n.items
+		b.addType(n.items, path+".*", vt.Elem(), visited)
+	case reflect.Map:
+		n.typ = "object"
+		n.items = newJSONSchemaNode()
+		b.nodes[path+".*"] = n.items
+		b.addType(n.items, path+".*", vt.Elem(), visited)
+	case reflect.Struct:
+		n.typ = "object"
+		b.addStruct(n, path, vt, visited)
+	}
+}
+
+// add the rules and default values to the nodes
+func (b *jsonSchemaBuilder) addRules(rules []*Rule, defValues map[string]interface{}) {
+	for _, r := range rules {
+		for _, field := range r.fields {
+			if !b.skipped(field) {
+				b.addRule(field, r)
+			}
+		}
+	}
+
+	for field, val := range defValues {
+		if b.skipped(field) {
+			continue
+		}
+		b.node(field).keywords["default"] = val
+	}
+}
+
+func (b *jsonSchemaBuilder) addRule(field string, r *Rule) {
+	n := b.node(field)
+	// the conditional rules cannot be expressed
+	if r.beforeFunc != nil || r.fieldCond != nil {
+		n.when = append(n.when, r.String())
+		return
+	}
+
+	if r.realName == "required" || r.realName == "requiredKey" {
+		parentPath, _ := splitFieldPath(field)
+		// required for the array elements or map keys is not need
+		if name, ok := b.requiredName(field); ok {
+			b.node(parentPath).addRequired(name)
+			// the "required" is present and not empty
+			n.notEmpty = n.notEmpty || r.realName == "required"
+			return
+		}
+	}
+
+	if r.anyOf != nil {
+		for _, alt := range r.anyOf {
+			an := newJSONSchemaNode()
+			if !applyJSONSchemaRule(an, alt) {
+				an.ext = append(an.ext, alt.String())
+			}
+			n.anyOf = append(n.anyOf, an)
+		}
+		return
+	}
+
+	if !applyJSONSchemaRule(n, r) {
+		n.ext = append(n.ext, r.String())
+	}
+}
+
+// requiredName get the property name of the field path for the "required" keyword
+func (b *jsonSchemaBuilder) requiredName(field string) (string, bool) {
+	_, name := splitFieldPath(field)
+	if name == "*" || name == keyPathSuffix[1:] {
+		return "", false
+	}
+
+	if pName, ok := b.names[field]; ok {
+		return pName, true
+	}
+	return name, true
+}
+
+// split the field path to parent path and last name. eg: "users.*.name" -> "users.*", "name"
+func splitFieldPath(path string) (parent, name string) {
+	if pos := strings.LastIndexByte(path, '.'); pos > -1 {
+		return path[:pos], path[pos+1:]
+	}
+	return "", path
+}
+
+// the string formats and patterns for the built-in validators
+var (
+	jsonSchemaFormats = map[string]string{
+		"isEmail":   "email",
+		"isFullURL": "uri",
+		"isIPv4":    "ipv4",
+		"isIPv6":    "ipv6",
+		"isUUID":    "uuid",
+		"isDNSName": "hostname",
+	}
+	jsonSchemaPatterns = map[string]*regexp.Regexp{
+		"isAlpha":        rxAlpha,
+		"isAlphaNum":     rxAlphaNum,
+		"isAlphaDash":    rxAlphaDash,
+		"isStringNumber": rxNumber,
+		"isHexadecimal":  rxHexadecimal,
+		"isHexColor":     rxHexColor,
+		"isCnMobile":     rxCnMobile,
+		"isUUID3":        rxUUID3,
+		"isUUID4":        rxUUID4,
+		"isUUID5":        rxUUID5,
+	}
+)
+
+// applyJSONSchemaRule map the built-in validator rule to the keywords of the node.
+// returns false on the rule cannot be expressed.
+func applyJSONSchemaRule(n *jsonSchemaNode, r *Rule) bool {
+	args := r.arguments
+	name := r.realName
+	if name == "" {
+		name = ValidatorName(r.validator)
+	}
+
+	if format, ok := jsonSchemaFormats[name]; ok {
+		n.setType("string")
+		n.set("format", format)
+		return true
+	}
+
+	if rx, ok := jsonSchemaPatterns[name]; ok {
+		n.setType("string")
+		n.set("pattern", rx.String())
+		return true
+	}
+
+	switch name {
+	// the number string or the non-negative integer. eg: "123", 123
+	case "isNumber", "isNumeric":
+		n.allOf = append(n.allOf, M{"anyOf": []M{
+			{"type": "string", "pattern": rxNumber.String()},
+			{"type": "integer", "minimum": int64(0)},
+		}})
+	case "isString":
+		n.setType("string")
+		return len(args) == 0 || setJSONSchemaLength(n, args[0], args[1:]...)
+	case "isInt":
+		n.setType("integer")
+		return setJSONSchemaNumbers(n, []string{"minimum", "maximum"}, args)
+	case "isUint":
+		n.setType("integer")
+		n.set("minimum", int64(0))
+	case "isFloat":
+		n.setType("number")
+	case "isBool":
+		n.setType("boolean")
+	case "isJSONType":
+		if len(args) == 0 {
+			return false
+		}
+
+		if len(args) == 1 {
+			n.setType(fmt.Sprint(args[0]))
+		} else if n.typ == "" {
+			types := make([]string, len(args))
+			for i, arg := range args {
+				types[i] = fmt.Sprint(arg)
+			}
+			n.set("type", types)
+		}
+	case "isMap":
+		n.setType("object")
+	case "isArray", "isSlice":
+		n.setType("array")
+	case "isInts", "isStrings":
+		n.setType("array")
+		if n.items == nil {
+			n.items = newJSONSchemaNode()
+		}
+
+		if name == "isInts" {
+			n.items.setType("integer")
+		} else {
+			n.items.setType("string")
+		}
+	case "length":
+		return len(args) == 1 && setJSONSchemaLength(n, args[0], args[0])
+	case "minLength":
+		return len(args) == 1 && setJSONSchemaLength(n, args[0])
+	case "maxLength":
+		return len(args) == 1 && setJSONSchemaLength(n, nil, args[0])
+	case "stringLength":
+		n.setType("string")
+		return len(args) > 0 && setJSONSchemaLength(n, args[0], args[1:]...)
+	case "min":
+		return setJSONSchemaNumbers(n, []string{"minimum"}, args)
+	case "max":
+		return setJSONSchemaNumbers(n, []string{"maximum"}, args)
+	case "gt":
+		return setJSONSchemaNumbers(n, []string{"exclusiveMinimum"}, args)
+	case "lt":
+		return setJSONSchemaNumbers(n, []string{"exclusiveMaximum"}, args)
+	case "between":
+		return len(args) == 2 && setJSONSchemaNumbers(n, []string{"minimum", "maximum"}, args)
+	case "enum", "notIn":
+		if len(args) != 1 {
+			return false
+		}
+
+		enum, ok := toInterfaceSlice(args[0])
+		if !ok {
+			return false
+		}
+
+		if name == "enum" {
+			n.set("enum", enum)
+		} else {
+			n.set("not", M{"enum": enum})
+		}
+	case "isEqual":
+		if len(args) != 1 {
+			return false
+		}
+		n.set("const", args[0])
+	case "notEqual":
+		if len(args) != 1 {
+			return false
+		}
+		n.set("not", M{"const": args[0]})
+	case "regexp":
+		pattern, ok := stringArg(args)
+		if !ok {
+			return false
+		}
+
+		n.setType("string")
+		n.set("pattern", pattern)
+	case "startsWith", "endsWith", "stringContains":
+		sub, ok := stringArg(args)
+		if !ok {
+			return false
+		}
+
+		pattern := regexp.QuoteMeta(sub)
+		if name == "startsWith" {
+			pattern = "^" + pattern
+		} else if name == "endsWith" {
+			pattern += "$"
+		}
+
+		n.setType("string")
+		n.set("pattern", pattern)
+	default:
+		return false
+	}
+	return true
+}
+
+// set min, max length of the node. the nil value is ignored.
+func setJSONSchemaLength(n *jsonSchemaNode, minLen interface{}, maxLen ...interface{}) bool {
+	if minLen != nil {
+		ln, err := filter.Int(minLen)
+		if err != nil || ln < 0 {
+			return false
+		}
+		n.minLen = ln
+	}
+
+	if len(maxLen) > 0 && maxLen[0] != nil {
+		ln, err := filter.Int(maxLen[0])
+		if err != nil || ln < 0 {
+			return false
+		}
+		n.maxLen = ln
+	}
+	return true
+}
+
+// set the number keywords by the args. eg: keys: ["minimum", "maximum"], args: ["1", "10"]
+func setJSONSchemaNumbers(n *jsonSchemaNode, keys []string, args []interface{}) bool {
+	if len(args) > len(keys) {
+		return false
+	}
+
+	nums := make([]interface{}, len(args))
+	for i, arg := range args {
+		num, ok := toJSONNumber(arg)
+		if !ok {
+			return false
+		}
+		nums[i] = num
+	}
+
+	for i, num := range nums {
+		n.set(keys[i], num)
+	}
+	return true
+}
+
+// toJSONNumber convert the number value to int64 or json.Number, keep the precision.
+func toJSONNumber(val interface{}) (interface{}, bool) {
+	if val == nil {
+		return nil, false
+	}
+
+	r, ok := toRat(val)
+	if !ok {
+		return nil, false
+	}
+
+	if r.IsInt() && r.Num().IsInt64() {
+		return r.Num().Int64(), true
+	}
+
+	// exactly decimal string. eg: "0.01"
+	if str, ok := toDecimalString(val); ok {
+		str = strings.TrimPrefix(strings.TrimSpace(str), "+")
+		if rxJSONNumber.MatchString(str) {
+			return json.Number(str), true
+		}
+	}
+
+	f, _ := new(big.Float).SetRat(r).Float64()
+	return f, true
+}
+
+var rxJSONNumber = regexp.MustCompile(`^-?(0|[1-9]\d*)(\.\d+)?([eE][-+]?\d+)?$`)
+
+// convert the numeric string values to number, for the integer or number type.
+func numericEnum(enum []interface{}) []interface{} {
+	nums := make([]interface{}, len(enum))
+	for i, val := range enum {
+		str, ok := val.(string)
+		if !ok {
+			nums[i] = val
+			continue
+		}
+
+		num, ok := toJSONNumber(str)
+		if !ok {
+			return enum
+		}
+		nums[i] = num
+	}
+	return nums
+}
+
+func toInterfaceSlice(val interface{}) ([]interface{}, bool) {
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	ss := make([]interface{}, rv.Len())
+	for i := range ss {
+		ss[i] = rv.Index(i).Interface()
+	}
+	return ss, true
+}
+
+// get the only one arg as string
+func stringArg(args []interface{}) (string, bool) {
+	if len(args) != 1 {
+		return "", false
+	}
+
+	str, ok := args[0].(string)
+	return str, ok
+}
+
+/*************************************************************
+ * JSON Schema import
+ *************************************************************/
+
+// JSONSchemaRules is the validation rules imported from a JSON Schema document.
+type JSONSchemaRules struct {
+	// Rules the string rules of the fields.
+	// eg: {"name": "required|isJSONType:string|stringLength:2,20"}
+	Rules MS
+	// Defaults the default values of the fields, from the "default" keyword.
+	Defaults M
+	// Messages the custom error messages, from the JSONSchemaMessagesKeyword.
+	// eg: {"name.required": "name is required"}
+	Messages MS
+	// Unsupported the JSON pointers of the keywords cannot be mapped to the validators.
+	// eg: ["/properties/tags/uniqueItems"]
+	Unsupported []string
+	// the required fields of the nested objects, they are checked on the parent is an object.
+	// eg: {"addr.city": true}
+	nested map[string]bool
+}
+
+// ParseJSONSchema parse the JSON Schema(draft 2020-12) document to the validation rules.
+// the keywords are mapped to the built-in validators, the others are reported in the Unsupported.
+//
+// Usage:
+// 	sr, err := validate.ParseJSONSchema(doc)
+// 	if len(sr.Unsupported) > 0 {
+// 		log.Println("unsupported keywords:", sr.Unsupported)
+// 	}
+//
+// 	v := validate.Map(data)
+// 	sr.Apply(v)
+// 	ok := v.Validate()
+func ParseJSONSchema(doc []byte) (*JSONSchemaRules, error) {
+	var root interface{}
+	// keep the precision of the numbers
+	if err := unmarshalJSON(doc, &root, true); err != nil {
+		return nil, err
+	}
+
+	sm, ok := root.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("validate: the JSON Schema document must be an object")
+	}
+
+	p := newJSONSchemaParser()
+	p.parse("", "", sm)
+	return p.result(), nil
+}
+
+// Apply add the rules and default values to the Validation.
+//
+// NOTICE: the required fields of the nested objects are only checked on the parent is an object,
+// it cannot be expressed by the string rules. so use the Apply() instead of the v.StringRules(sr.Rules).
+func (sr *JSONSchemaRules) Apply(v *Validation) *Validation {
+	n := len(v.rules)
+	v.StringRules(sr.Rules)
+	// like the JSON Schema, the keywords are applied on the field exists, even the value is empty.
+	for _, r := range v.rules[n:] {
+		r.skipEmpty = false
+		r.skipMissing = true
+
+		if r.realName == "requiredKey" && sr.nested[r.fields[0]] {
+			r.fieldCond = parentIsObject
+		}
+	}
+	for field, val := range sr.Defaults {
+		v.SetDefValue(field, val)
+	}
+
+	if len(sr.Messages) > 0 {
+		v.AddMessages(sr.Messages)
+	}
+	return v
+}
+
+// Schema compile the rules to an reusable Schema.
+func (sr *JSONSchemaRules) Schema() *Schema {
+	return NewSchema(func(v *Validation) {
+		sr.Apply(v)
+	})
+}
+
+// the parent of the field is an object. eg: the "addr" for the "addr.city"
+func parentIsObject(v *Validation, field string) bool {
+	parent, _ := splitFieldPath(field)
+	val, ok := v.Get(parent)
+	if !ok || val == nil {
+		return false
+	}
+
+	kind := removeValuePtr(reflect.ValueOf(val)).Kind()
+	return kind == reflect.Map || kind == reflect.Struct
+}
+
+// jsonSchemaParser parse the JSON Schema to the string rules of the field paths.
+type jsonSchemaParser struct {
+	// field path to the rule items. eg: {"users.*.name": ["isJSONType:string"]}
+	rules    map[string][]string
+	required map[string]bool
+	// the required fields of the nested objects. eg: {"addr.city": true}
+	nested   map[string]bool
+	defaults M
+	messages MS
+	// JSON pointers of the unsupported keywords
+	unsupported []string
+}
+
+func newJSONSchemaParser() *jsonSchemaParser {
+	return &jsonSchemaParser{
+		rules:    make(map[string][]string),
+		required: make(map[string]bool),
+		nested:   make(map[string]bool),
+		defaults: make(M),
+		messages: make(MS),
+	}
+}
+
+// result build the JSONSchemaRules by the parsed rules. the "requiredKey" is the first rule,
+// it only checks the field exists, the zero value is allowed. eg: {"count": 0}
+func (p *jsonSchemaParser) result() *JSONSchemaRules {
+	sr := &JSONSchemaRules{
+		Rules:       make(MS, len(p.rules)),
+		Defaults:    p.defaults,
+		Messages:    p.messages,
+		Unsupported: p.unsupported,
+		nested:      p.nested,
+	}
+
+	for field, items := range p.rules {
+		sr.Rules[field] = strings.Join(typeFirst(items), "|")
+	}
+
+	for field := range p.required {
+		if rule := sr.Rules[field]; rule != "" {
+			sr.Rules[field] = "requiredKey|" + rule
+		} else {
+			sr.Rules[field] = "requiredKey"
+		}
+	}
+	return sr
+}
+
+// move the "isJSONType" to the first, the type error is reported before the other keywords.
+// eg: {"count": null} is reported by the "isJSONType:integer" instead of "min:0"
+func typeFirst(items []string) []string {
+	for i, item := range items {
+		if i > 0 && strings.HasPrefix(item, "isJSONType:") {
+			sorted := append([]string{item}, items[:i]...)
+			return append(sorted, items[i+1:]...)
+		}
+	}
+	return items
+}
+
+// the annotation keywords, they don't affect the validation.
+var jsonSchemaAnnotations = map[string]bool{
+	"$schema":     true,
+	"$id":         true,
+	"$comment":    true,
+	"$anchor":     true,
+	"title":       true,
+	"description": true,
+	"examples":    true,
+	"deprecated":  true,
+	"readOnly":    true,
+	"writeOnly":   true,
+}
+
+// the string formats to the validators
+var jsonSchemaFormatValidators = map[string]string{
+	"email":     "isEmail",
+	"uri":       "isFullURL",
+	"ipv4":      "isIPv4",
+	"ipv6":      "isIPv6",
+	"uuid":      "isUUID",
+	"hostname":  "isDNSName",
+	"date":      "isDate",
+	"date-time": "isDate",
+}
+
+// parse the schema s for the field path. ptr is the JSON pointer of the schema.
+func (p *jsonSchemaParser) parse(path, ptr string, s map[string]interface{}) {
+	var minLen, maxLen interface{}
+	for _, key := range sortedMapKeys(s) {
+		val := s[key]
+		kPtr := ptr + "/" + escapeJSONPointer(key)
+		if jsonSchemaAnnotations[key] {
+			continue
+		}
+
+		switch key {
+		case "type":
+			types, ok := jsonStrings(val)
+			if !ok {
+				p.unsupported = append(p.unsupported, kPtr)
+			} else if path != "" {
+				p.add(path, kPtr, "isJSONType:"+strings.Join(types, ","))
+			} else if len(types) != 1 || types[0] != "object" {
+				// the root must be an object
+				p.unsupported = append(p.unsupported, kPtr)
+			}
+		case "required":
+			names, ok := jsonStrings(val)
+			if !ok {
+				p.unsupported = append(p.unsupported, kPtr)
+				continue
+			}
+
+			for _, name := range names {
+				field := joinFieldPath(path, name)
+				p.required[field] = true
+				// the required of the nested object is checked on the parent is an object.
+				if path != "" {
+					p.nested[field] = true
+				}
+			}
+		case "properties":
+			props, ok := val.(map[string]interface{})
+			if !ok {
+				p.unsupported = append(p.unsupported, kPtr)
+				continue
+			}
+
+			for _, name := range sortedMapKeys(props) {
+				// the name cannot be used in the field path
+				if name == "" || strings.ContainsAny(name, ".*") {
+					p.unsupported = append(p.unsupported, kPtr+"/"+escapeJSONPointer(name))
+					continue
+				}
+				p.parseSub(joinFieldPath(path, name), kPtr+"/"+escapeJSONPointer(name), props[name])
+			}
+		case "items":
+			p.parseSub(joinFieldPath(path, "*"), kPtr, val)
+		case "additionalProperties":
+			// the "*" will match the defined properties too
+			if _, has := s["properties"]; has && val != true {
+				p.unsupported = append(p.unsupported, kPtr)
+				continue
+			}
+			p.parseSub(joinFieldPath(path, "*"), kPtr, val)
+		case "propertyNames":
+			p.parseSub(path+keyPathSuffix, kPtr, val)
+		case "minLength", "maxLength":
+			if _, ok := jsonInteger(val); !ok {
+				p.unsupported = append(p.unsupported, kPtr)
+			} else if key == "minLength" {
+				minLen = val
+			} else {
+				maxLen = val
+			}
+		case "minItems", "minProperties":
+			p.addNumber(path, kPtr, "minLen", val, true)
+		case "maxItems", "maxProperties":
+			p.addNumber(path, kPtr, "maxLen", val, true)
+		case "minimum":
+			p.addNumber(path, kPtr, "min", val, false)
+		case "maximum":
+			p.addNumber(path, kPtr, "max", val, false)
+		case "exclusiveMinimum":
+			p.addNumber(path, kPtr, "gt", val, false)
+		case "exclusiveMaximum":
+			p.addNumber(path, kPtr, "lt", val, false)
+		case "enum":
+			if args, ok := jsonEnumArgs(val); ok {
+				p.add(path, kPtr, "in:"+args)
+			} else {
+				p.unsupported = append(p.unsupported, kPtr)
+			}
+		case "const":
+			if rule, ok := jsonConstRule(val); ok {
+				p.add(path, kPtr, rule)
+			} else {
+				p.unsupported = append(p.unsupported, kPtr)
+			}
+		case "not":
+			p.parseNot(path, kPtr, val)
+		case "pattern":
+			if pattern, ok := val.(string); ok {
+				p.add(path, kPtr, "regexp:"+quoteRuleArg(pattern, true))
+			} else {
+				p.unsupported = append(p.unsupported, kPtr)
+			}
+		case "format":
+			if name, ok := jsonSchemaFormatValidators[fmt.Sprint(val)]; ok {
+				p.add(path, kPtr, name)
+			} else {
+				p.unsupported = append(p.unsupported, kPtr)
+			}
+		case "default":
+			if path == "" {
+				p.unsupported = append(p.unsupported, kPtr)
+			} else {
+				p.defaults[path] = jsonValue(val)
+			}
+		case "allOf":
+			subs, ok := val.([]interface{})
+			if !ok {
+				p.unsupported = append(p.unsupported, kPtr)
+				continue
+			}
+
+			for i, sub := range subs {
+				p.parseSub(path, kPtr+"/"+strconv.Itoa(i), sub)
+			}
+		case "anyOf":
+			p.parseAnyOf(path, kPtr, val)
+		case JSONSchemaMessagesKeyword:
+			msgs, ok := val.(map[string]interface{})
+			if !ok || path == "" {
+				p.unsupported = append(p.unsupported, kPtr)
+				continue
+			}
+
+			for name, msg := range msgs {
+				// the "required" is mapped to the "requiredKey"
+				if name == "required" {
+					name = "requiredKey"
+				}
+				p.messages[path+"."+name] = fmt.Sprint(msg)
+			}
+		case JSONSchemaExtKeyword:
+			// the rules exported by the JSONSchema()
+			rules, ok := jsonStrings(val)
+			if !ok {
+				p.unsupported = append(p.unsupported, kPtr)
+				continue
+			}
+
+			for _, rule := range rules {
+				p.add(path, kPtr, rule)
+			}
+		default:
+			p.unsupported = append(p.unsupported, kPtr)
+		}
+	}
+
+	// "stringLength:min,max"
+	if minLen != nil || maxLen != nil {
+		if minLen == nil {
+			minLen = 0
+		}
+
+		rule := "stringLength:" + fmt.Sprint(minLen)
+		if maxLen != nil {
+			rule += "," + fmt.Sprint(maxLen)
+		}
+		p.add(path, ptr, rule)
+	}
+}
+
+// parse the sub schema, it can be an object or bool schema.
+func (p *jsonSchemaParser) parseSub(path, ptr string, val interface{}) {
+	switch sub := val.(type) {
+	case map[string]interface{}:
+		p.parse(path, ptr, sub)
+	case bool:
+		// the false schema cannot be expressed
+		if !sub {
+			p.unsupported = append(p.unsupported, ptr)
+		}
+	default:
+		p.unsupported = append(p.unsupported, ptr)
+	}
+}
+
+// parse the "not" keyword, only support the "enum" and "const".
+func (p *jsonSchemaParser) parseNot(path, ptr string, val interface{}) {
+	sub, ok := val.(map[string]interface{})
+	if ok && len(sub) == 1 {
+		if enum, has := sub["enum"]; has {
+			if args, ok := jsonEnumArgs(enum); ok {
+				p.add(path, ptr, "notIn:"+args)
+				return
+			}
+		}
+
+		if str, has := sub["const"].(string); has {
+			p.add(path, ptr, "ne:"+quoteRuleArg(str, false))
+			return
+		}
+	}
+	p.unsupported = append(p.unsupported, ptr)
+}
+
+// parse the "anyOf" keyword to a rule group. each alternative must be mapped to one validator.
+// eg: [{"format": "email"}, {"pattern": "^1\\d{10}$"}] -> "(isEmail or regexp:'^1\d{10}$')"
+func (p *jsonSchemaParser) parseAnyOf(path, ptr string, val interface{}) {
+	subs, ok := val.([]interface{})
+	if !ok || len(subs) == 0 {
+		p.unsupported = append(p.unsupported, ptr)
+		return
+	}
+
+	alts := make([]string, 0, len(subs))
+	for _, sub := range subs {
+		sm, ok := sub.(map[string]interface{})
+		if !ok {
+			p.unsupported = append(p.unsupported, ptr)
+			return
+		}
+
+		// parse as a property, the root path cannot have rules.
+		sp := newJSONSchemaParser()
+		sp.parse("_", "", sm)
+
+		items := dropStringType(sp.rules["_"])
+		if len(sp.unsupported) > 0 || len(sp.rules) != 1 || len(items) != 1 || len(sp.required) > 0 ||
+			len(sp.defaults) > 0 || strings.HasPrefix(items[0], "(") {
+			p.unsupported = append(p.unsupported, ptr)
+			return
+		}
+		alts = append(alts, items[0])
+	}
+
+	p.add(path, ptr, "("+strings.Join(alts, " or ")+")")
+}
+
+// drop the "isJSONType:string" on the other validator only accepts string. eg: "isEmail", "regexp"
+func dropStringType(items []string) []string {
+	if len(items) != 2 {
+		return items
+	}
+
+	for i, item := range items {
+		other := items[1-i]
+		name := strings.SplitN(other, ":", 2)[0]
+		if item == "isJSONType:string" && (name == "regexp" || name == "stringLength" || jsonSchemaFormats[name] != "") {
+			return []string{other}
+		}
+	}
+	return items
+}
+
+// add the rule item for the field path. the root path cannot have rules.
+func (p *jsonSchemaParser) add(path, ptr, item string) {
+	if path == "" {
+		p.unsupported = append(p.unsupported, ptr)
+		return
+	}
+	p.rules[path] = append(p.rules[path], item)
+}
+
+// add the validator with a number arg. eg: "min:0.01"
+func (p *jsonSchemaParser) addNumber(path, ptr, validator string, val interface{}, isInt bool) {
+	num, ok := val.(json.Number)
+	if isInt {
+		_, ok = jsonInteger(val)
+	}
+
+	if !ok {
+		p.unsupported = append(p.unsupported, ptr)
+		return
+	}
+	p.add(path, ptr, validator+":"+num.String())
+}
+
+// quoteRuleArg quote the argument for the string rule, if it contains the special chars.
+// always quote it on the force is true.
+func quoteRuleArg(arg string, force bool) string {
+	if !force && arg != "" && arg == strings.TrimSpace(arg) && !strings.ContainsAny(arg, `,|()'"\: `) {
+		return arg
+	}
+
+	arg = strings.Replace(arg, `\`, `\\`, -1)
+	return "'" + strings.Replace(arg, "'", `\'`, -1) + "'"
+}
+
+// join the parent path and the name. eg: "users", "*" -> "users.*"
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// escape the name for the JSON pointer. see RFC 6901
+func escapeJSONPointer(name string) string {
+	name = strings.Replace(name, "~", "~0", -1)
+	return strings.Replace(name, "/", "~1", -1)
+}
+
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// get the string or string list. eg: "string", ["string", "null"]
+func jsonStrings(val interface{}) ([]string, bool) {
+	if str, ok := val.(string); ok {
+		return []string{str}, true
+	}
+
+	list, ok := val.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, false
+	}
+
+	ss := make([]string, len(list))
+	for i, item := range list {
+		if ss[i], ok = item.(string); !ok {
+			return nil, false
+		}
+	}
+	return ss, true
+}
+
+// get the non-negative integer value
+func jsonInteger(val interface{}) (int64, bool) {
+	num, ok := val.(json.Number)
+	if !ok {
+		return 0, false
+	}
+
+	i64, err := num.Int64()
+	return i64, err == nil && i64 >= 0
+}
+
+// the enum values must be all strings, or all integers.
+// eg: ["a", "b,c"] -> "a,'b,c'"
+func jsonEnumArgs(val interface{}) (string, bool) {
+	list, ok := val.([]interface{})
+	if !ok || len(list) == 0 {
+		return "", false
+	}
+
+	_, isStr := list[0].(string)
+	args := make([]string, len(list))
+	for i, item := range list {
+		switch tv := item.(type) {
+		case string:
+			if !isStr {
+				return "", false
+			}
+			args[i] = quoteRuleArg(tv, false)
+		case json.Number:
+			if _, err := tv.Int64(); isStr || err != nil {
+				return "", false
+			}
+			args[i] = tv.String()
+		default:
+			return "", false
+		}
+	}
+	return strings.Join(args, ","), true
+}
+
+// the const value allow string and number.
+func jsonConstRule(val interface{}) (string, bool) {
+	switch tv := val.(type) {
+	case string:
+		return "eq:" + quoteRuleArg(tv, false), true
+	case json.Number:
+		// the number is compared by the value, not the type
+		return "between:" + tv.String() + "," + tv.String(), true
+	}
+	return "", false
+}
+
+// convert the json.Number to int64 or float64
+func jsonValue(val interface{}) interface{} {
+	num, ok := val.(json.Number)
+	if !ok {
+		return val
+	}
+
+	if i64, err := num.Int64(); err == nil {
+		return i64
+	}
+
+	if f64, err := num.Float64(); err == nil {
+		return f64
+	}
+	return val
+}
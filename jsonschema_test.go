@@ -0,0 +1,449 @@
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidation_JSONSchema(t *testing.T) {
+	is := assert.New(t)
+
+	v := New(M{})
+	v.StringRules(MS{
+		"name":        "required|string|minLen:2|maxLen:20",
+		"email":       "required|email",
+		"age":         "int:1,120",
+		"price":       "min:0.01|lt:99999999999999999999.99",
+		"role":        "in:admin,user",
+		"level":       "int|enum:1,2,3",
+		"code":        `regexp:"^[A-Z]{3}$"`,
+		"password":    "required|minLen:6",
+		"password2":   "eqField:password",
+		"contact":     "(email or isCnMobile or isIP)",
+		"tags":        "slice|minLen:1|maxLen:5",
+		"tags.*":      "string|startsWith:t_",
+		"users.*.id":  "required|uint",
+		"meta.#key":   "isAlphaDash",
+		"status":      "default:on|isAlpha",
+		"score":       "between:0,100",
+		"confirm":     "when:password|required",
+		"mobile":      "isCnMobile|notIn:13800000000",
+		"description": "stringContains:go|endsWith:.",
+		"nick":        "jsonType:string,null",
+	})
+
+	doc := v.JSONSchema()
+	bs, err := json.Marshal(doc)
+	is.NoError(err)
+
+	got := M{}
+	is.NoError(json.Unmarshal(bs, &got))
+	is.Equal(JSONSchemaDraft, got["$schema"])
+	is.Equal("object", got["type"])
+	is.Equal([]interface{}{"email", "name", "password"}, got["required"])
+
+	props := got["properties"].(map[string]interface{})
+	prop := func(name string) map[string]interface{} {
+		return props[name].(map[string]interface{})
+	}
+
+	is.Equal(map[string]interface{}{"type": "string", "minLength": 2.0, "maxLength": 20.0}, prop("name"))
+	// the required string is not empty
+	is.Equal(map[string]interface{}{"type": "string", "format": "email", "minLength": 1.0}, prop("email"))
+	is.Equal(map[string]interface{}{"type": "integer", "minimum": 1.0, "maximum": 120.0}, prop("age"))
+	is.Equal(`{"exclusiveMaximum":99999999999999999999.99,"minimum":0.01}`, jsonString(doc["properties"].(M)["price"]))
+	is.Equal([]interface{}{"admin", "user"}, prop("role")["enum"])
+	is.Equal([]interface{}{1.0, 2.0, 3.0}, prop("level")["enum"])
+	is.Equal("^[A-Z]{3}$", prop("code")["pattern"])
+	is.Equal([]interface{}{"eqField:password"}, prop("password2")[JSONSchemaExtKeyword])
+	is.Equal(map[string]interface{}{"minimum": 0.0, "maximum": 100.0}, prop("score"))
+	is.Equal([]interface{}{"required"}, prop("confirm")[JSONSchemaWhenKeyword])
+	is.Equal(map[string]interface{}{"enum": []interface{}{"13800000000"}}, prop("mobile")["not"])
+	is.Equal(rxCnMobile.String(), prop("mobile")["pattern"])
+
+	is.Equal([]interface{}{"string", "null"}, prop("nick")["type"])
+
+	// rule group
+	alts := prop("contact")["anyOf"].([]interface{})
+	is.Len(alts, 3)
+	is.Equal(map[string]interface{}{"type": "string", "format": "email"}, alts[0])
+	is.Equal("string", alts[1].(map[string]interface{})["type"])
+	is.Equal([]interface{}{"isIP"}, alts[2].(map[string]interface{})[JSONSchemaExtKeyword])
+
+	// array and items
+	tags := prop("tags")
+	is.Equal("array", tags["type"])
+	is.Equal(1.0, tags["minItems"])
+	is.Equal(5.0, tags["maxItems"])
+	is.Equal(map[string]interface{}{"type": "string", "pattern": "^t_"}, tags["items"])
+
+	users := prop("users")
+	is.Equal("array", users["type"])
+	item := users["items"].(map[string]interface{})
+	is.Equal([]interface{}{"id"}, item["required"])
+	// the empty value of the integer cannot be expressed
+	is.Equal(map[string]interface{}{"type": "integer", "minimum": 0.0, JSONSchemaExtKeyword: []interface{}{"required"}}, item["properties"].(map[string]interface{})["id"])
+
+	// map keys
+	meta := prop("meta")
+	is.Equal("object", meta["type"])
+	is.Equal(map[string]interface{}{"type": "string", "pattern": rxAlphaDash.String()}, meta["propertyNames"])
+
+	// default value
+	is.Equal(map[string]interface{}{"type": "string", "pattern": rxAlpha.String(), "default": "on"}, prop("status"))
+
+	// multi pattern
+	desc := prop("description")
+	is.Equal("go", desc["pattern"])
+	is.Equal([]interface{}{map[string]interface{}{"pattern": `\.$`}}, desc["allOf"])
+
+	// compiled schema
+	s := v.Compile()
+	is.Equal(jsonString(doc), jsonString(s.JSONSchema()))
+}
+
+func TestValidation_JSONSchema_required(t *testing.T) {
+	is := assert.New(t)
+
+	v := New(M{})
+	v.StringRules(MS{
+		"name":  "required|string",
+		"nick":  "required",
+		"tags":  "required|slice",
+		"count": "isNumber",
+		"code":  "isNumeric",
+	})
+
+	doc := v.JSONSchema()
+	is.Equal([]string{"name", "nick", "tags"}, doc["required"])
+
+	props := doc["properties"].(M)
+	is.Equal(M{"type": "string", "minLength": 1}, props["name"])
+	// the type is unknown, keep the rule
+	is.Equal(M{JSONSchemaExtKeyword: []string{"required"}}, props["nick"])
+	is.Equal(M{"type": "array", "minItems": 1}, props["tags"])
+	// the number string or the non-negative integer
+	numbers := `{"allOf":[{"anyOf":[{"pattern":"^[0-9]+$","type":"string"},{"minimum":0,"type":"integer"}]}]}`
+	is.Equal(numbers, jsonString(props["count"]))
+	is.Equal(numbers, jsonString(props["code"]))
+}
+
+func TestStructJSONSchema(t *testing.T) {
+	is := assert.New(t)
+
+	type Base struct {
+		ID uint `json:"id" validate:"required|min:1"`
+	}
+
+	type Address struct {
+		City string `json:"city" validate:"required|maxLen:32"`
+	}
+
+	type user struct {
+		Base
+		Name      string            `json:"name,omitempty" validate:"required|minLen:2"`
+		Age       int               `json:"age" validate:"between:1,120"`
+		Score     float64           `validate:"gt:0"`
+		Status    int               `json:"status" validate:"in:1,2"`
+		Email     string            `json:"email" validate:"email"`
+		Tags      []string          `json:"tags" validate:"maxLen:3"`
+		Labels    map[string]string `json:"labels" validateKey:"isAlphaDash"`
+		Addresses []*Address        `json:"addresses" validate:"minLen:1"`
+		Home      Address           `json:"home"`
+		CreatedAt time.Time         `json:"created_at" validate:"required"`
+		Avatar    []byte            `json:"-"`
+		Password  string            `json:"-" validate:"required|minLen:6"`
+		Secret    Address           `json:"-"`
+		Dash      string            `json:"-," validate:"maxLen:8"`
+		Amount    json.Number       `json:"amount" validate:"min:0.01"`
+	}
+
+	doc, err := StructJSONSchema(&user{})
+	is.NoError(err)
+
+	got := M{}
+	is.NoError(json.Unmarshal([]byte(jsonString(doc)), &got))
+	is.Equal([]interface{}{"id", "name", "created_at"}, got["required"])
+
+	props := got["properties"].(map[string]interface{})
+	prop := func(name string) map[string]interface{} {
+		return props[name].(map[string]interface{})
+	}
+
+	is.Equal(map[string]interface{}{"type": "integer", "minimum": 1.0, JSONSchemaExtKeyword: []interface{}{"required"}}, prop("id"))
+	is.Equal(map[string]interface{}{"type": "string", "minLength": 2.0}, prop("name"))
+	is.Equal(map[string]interface{}{"type": "integer", "minimum": 1.0, "maximum": 120.0}, prop("age"))
+	is.Equal(map[string]interface{}{"type": "number", "exclusiveMinimum": 0.0}, prop("Score"))
+	is.Equal([]interface{}{1.0, 2.0}, prop("status")["enum"])
+	is.Equal("email", prop("email")["format"])
+	is.Equal(map[string]interface{}{"type": "array", "maxItems": 3.0, "items": map[string]interface{}{"type": "string"}}, prop("tags"))
+	is.Equal(rxAlphaDash.String(), prop("labels")["propertyNames"].(map[string]interface{})["pattern"])
+	is.Equal(map[string]interface{}{"type": "string"}, prop("labels")["additionalProperties"])
+	is.Equal(map[string]interface{}{"type": "string", "format": "date-time", "minLength": 1.0}, prop("created_at"))
+	// skip the fields like encoding/json
+	is.NotContains(props, "Avatar")
+	is.NotContains(props, "Password")
+	is.NotContains(props, "Secret")
+	is.NotContains(props, "city")
+	is.Equal(map[string]interface{}{"type": "string", "maxLength": 8.0}, prop("-"))
+	is.Equal(map[string]interface{}{"type": "number", "minimum": 0.01}, prop("amount"))
+
+	addresses := prop("addresses")
+	is.Equal(1.0, addresses["minItems"])
+	city := map[string]interface{}{"type": "string", "minLength": 1.0, "maxLength": 32.0}
+	is.Equal(city, addresses["items"].(map[string]interface{})["properties"].(map[string]interface{})["city"])
+	is.Equal([]interface{}{"city"}, addresses["items"].(map[string]interface{})["required"])
+	is.Equal(city, prop("home")["properties"].(map[string]interface{})["city"])
+
+	_, err = StructJSONSchema("invalid")
+	is.Error(err)
+
+	type invalid struct {
+		Name string `validate:"required|(email or"`
+	}
+	_, err = StructJSONSchema(invalid{})
+	is.Error(err)
+}
+
+func TestParseJSONSchema(t *testing.T) {
+	is := assert.New(t)
+
+	doc := `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "user",
+	"type": "object",
+	"required": ["name", "email", "tags"],
+	"properties": {
+		"name": {"type": "string", "minLength": 2, "maxLength": 20},
+		"email": {"type": "string", "format": "email"},
+		"age": {"type": "integer", "minimum": 1, "maximum": 120},
+		"price": {"type": ["number", "string"], "exclusiveMinimum": 0, "exclusiveMaximum": 99999999999999999999.99},
+		"role": {"enum": ["admin", "user,guest"], "default": "admin"},
+		"level": {"enum": [1, 2, 3], "not": {"const": 2}},
+		"code": {"type": "string", "pattern": "^[A-Z]{3}(|\\d)$"},
+		"kind": {"const": "person", "not": {"enum": ["x"]}},
+		"tags": {"type": "array", "minItems": 1, "items": {"type": "string", "maxLength": 8}, "uniqueItems": true},
+		"users": {"type": "array", "items": {"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}}},
+		"meta": {"type": "object", "propertyNames": {"pattern": "^[a-z]+$"}, "additionalProperties": {"type": "string"}},
+		"contact": {"anyOf": [{"format": "email"}, {"pattern": "^1\\d{10}$"}]},
+		"pwd2": {"x-validate": ["eqField:pwd"], "x-validate-when": ["required"]},
+		"bad": {"oneOf": [{"type": "string"}], "anyOf": [{"type": "integer", "minimum": 1}], "format": "ipv9"},
+		"a.b": {"type": "string"}
+	},
+	"$defs": {"id": {"type": "integer"}}
+}`
+
+	sr, err := ParseJSONSchema([]byte(doc))
+	is.NoError(err)
+
+	is.Equal("requiredKey|isJSONType:string|stringLength:2,20", sr.Rules["name"])
+	is.Equal("requiredKey|isJSONType:string|isEmail", sr.Rules["email"])
+	is.Equal("isJSONType:integer|max:120|min:1", sr.Rules["age"])
+	is.Equal("isJSONType:number,string|lt:99999999999999999999.99|gt:0", sr.Rules["price"])
+	is.Equal("in:admin,'user,guest'", sr.Rules["role"])
+	is.Equal(`isJSONType:string|regexp:'^[A-Z]{3}(|\\d)$'`, sr.Rules["code"])
+	is.Equal("eq:person|notIn:x", sr.Rules["kind"])
+	is.Equal("requiredKey|isJSONType:array|minLen:1", sr.Rules["tags"])
+	is.Equal("isJSONType:string|stringLength:0,8", sr.Rules["tags.*"])
+	is.Equal("requiredKey|isJSONType:integer", sr.Rules["users.*.id"])
+	is.Equal("isJSONType:string", sr.Rules["meta.*"])
+	is.Equal("regexp:'^[a-z]+$'", sr.Rules["meta.#key"])
+	is.Equal(`(isEmail or regexp:'^1\\d{10}$')`, sr.Rules["contact"])
+	is.Equal("eqField:pwd", sr.Rules["pwd2"])
+	is.Equal(M{"role": "admin"}, sr.Defaults)
+	is.Equal([]string{
+		"/$defs",
+		"/properties/a.b",
+		"/properties/bad/anyOf",
+		"/properties/bad/format",
+		"/properties/bad/oneOf",
+		"/properties/level/not",
+		"/properties/pwd2/x-validate-when",
+		"/properties/tags/uniqueItems",
+	}, sr.Unsupported)
+
+	// validate the JSON data
+	d, err := FromJSON(`{
+	"name": "inhere",
+	"email": "some@example.com",
+	"age": 20,
+	"price": "12345678901234567890.12",
+	"level": 1,
+	"code": "ABC1",
+	"tags": ["go", "php"],
+	"users": [{"id": 1}, {"id": 2}],
+	"meta": {"key": "val"},
+	"contact": "13800000000"
+}`)
+	is.NoError(err)
+	v := sr.Apply(d.Create())
+	v.StopOnError = false
+	is.True(v.Validate(), v.Errors.String())
+	is.Equal("admin", v.SafeVal("role"))
+
+	d, err = FromJSON(`{
+	"name": "i",
+	"email": "invalid",
+	"age": 20.5,
+	"role": "guest",
+	"tags": ["too long tag"],
+	"users": [{"id": 1}, {"name": "tom"}],
+	"meta": {"Key": "val"},
+	"contact": "abc"
+}`)
+	is.NoError(err)
+	v = sr.Apply(d.Create())
+	v.StopOnError = false
+	is.False(v.Validate())
+	is.Equal([]string{"age", "contact", "email", "meta.Key", "name", "role", "tags.0", "users.1.id"}, sortedFields(v.FieldErrors()))
+
+	res := sr.Schema().Validate(d, "")
+	is.True(res.IsFail())
+	is.Len(res.FieldErrors(), 1)
+
+	// invalid document
+	_, err = ParseJSONSchema([]byte(`[]`))
+	is.Error(err)
+	_, err = ParseJSONSchema([]byte(`{invalid`))
+	is.Error(err)
+}
+
+func TestParseJSONSchema_zeroValue(t *testing.T) {
+	is := assert.New(t)
+
+	sr, err := ParseJSONSchema([]byte(`{
+	"type": "object",
+	"required": ["count", "enabled", "name"],
+	"properties": {
+		"count": {"type": "integer", "minimum": 0},
+		"enabled": {"type": "boolean"},
+		"name": {"type": "string", "minLength": 2}
+	}
+}`))
+	is.NoError(err)
+	is.Equal("requiredKey|isJSONType:integer|min:0", sr.Rules["count"])
+
+	s := sr.Schema()
+	tests := []struct {
+		data   string
+		fields []string
+	}{
+		// the "required" only checks the key exists
+		{`{"count": 0, "enabled": false, "name": "ab"}`, nil},
+		{`{"count": 1, "enabled": true}`, []string{"name"}},
+		// the empty value is validated like the other values
+		{`{"count": 0, "enabled": false, "name": ""}`, []string{"name"}},
+		{`{"count": null, "enabled": false, "name": "ab"}`, []string{"count"}},
+		{`{"count": -1, "enabled": false, "name": "ab"}`, []string{"count"}},
+	}
+
+	for _, tt := range tests {
+		d, err := FromJSON(tt.data)
+		is.NoError(err)
+
+		res := s.Validate(d, "")
+		is.Equal(tt.fields, res.FieldErrors().Fields(), tt.data)
+	}
+
+	d, _ := FromJSON(`{"enabled": false, "name": "ab"}`)
+	is.Equal("count is required", s.Validate(d, "").FieldErrors().One())
+	d, _ = FromJSON(`{"count": null, "enabled": false, "name": "ab"}`)
+	is.Equal("isJSONType", s.Validate(d, "").FieldErrors()[0].Validator)
+}
+
+func TestParseJSONSchema_nestedRequired(t *testing.T) {
+	is := assert.New(t)
+
+	sr, err := ParseJSONSchema([]byte(`{
+	"type": "object",
+	"properties": {
+		"addr": {
+			"type": "object",
+			"required": ["city"],
+			"properties": {"city": {"type": "string"}}
+		},
+		"users": {"type": "array", "items": {
+			"type": "object",
+			"required": ["name"],
+			"properties": {
+				"name": {"type": "string"},
+				"home": {"type": "object", "required": ["city"]}
+			}
+		}}
+	}
+}`))
+	is.NoError(err)
+	is.Empty(sr.Unsupported)
+
+	s := sr.Schema()
+	tests := []struct {
+		data   string
+		fields []string
+	}{
+		// the parent is not exists
+		{`{}`, nil},
+		{`{"users": [{"name": "tom"}]}`, nil},
+		{`{"addr": null}`, []string{"addr"}},
+		// the parent exists
+		{`{"addr": {}}`, []string{"addr.city"}},
+		{`{"addr": {"city": ""}}`, nil},
+		{`{"users": [{"name": "tom"}, {}]}`, []string{"users.1.name"}},
+		{`{"users": [{"name": "tom", "home": {}}]}`, []string{"users.0.home.city"}},
+		{`{"users": [{"name": "tom", "home": {"city": "a"}}]}`, nil},
+	}
+
+	for _, tt := range tests {
+		d, err := FromJSON(tt.data)
+		is.NoError(err)
+
+		res := s.Validate(d, "")
+		is.Equal(tt.fields, res.FieldErrors().Fields(), tt.data)
+	}
+}
+
+func TestJSONSchema_roundTrip(t *testing.T) {
+	is := assert.New(t)
+
+	v := New(M{})
+	v.StringRules(MS{
+		"name":   "required|string|minLen:2|maxLen:8",
+		"age":    "int|between:1,120",
+		"email":  "(email or isCnMobile)",
+		"tags.*": "in:go,php",
+		"pwd2":   "eqField:pwd",
+	})
+
+	sr, err := ParseJSONSchema([]byte(jsonString(v.JSONSchema())))
+	is.NoError(err)
+	is.Empty(sr.Unsupported)
+	is.Equal("eqField:pwd", sr.Rules["pwd2"])
+
+	s := sr.Schema()
+	tests := []struct {
+		data M
+		ok   bool
+	}{
+		{M{"name": "inhere", "age": 20, "email": "13800000000", "tags": []string{"go"}, "pwd": "a", "pwd2": "a"}, true},
+		{M{"name": "i"}, false},
+		{M{"name": "inhere", "age": 200}, false},
+		{M{"name": "inhere", "email": "abc"}, false},
+		{M{"name": "inhere", "tags": []string{"java"}}, false},
+		{M{"name": "inhere", "pwd": "a", "pwd2": "b"}, false},
+	}
+
+	for _, tt := range tests {
+		nv := New(tt.data)
+		nv.StringRules(MS{
+			"name":   "required|string|minLen:2|maxLen:8",
+			"age":    "int|between:1,120",
+			"email":  "(email or isCnMobile)",
+			"tags.*": "in:go,php",
+			"pwd2":   "eqField:pwd",
+		})
+		is.Equal(tt.ok, nv.Validate(), tt.data)
+		is.Equal(tt.ok, s.Validate(FromMap(tt.data), "").IsOK(), tt.data)
+	}
+}
@@ -20,25 +20,28 @@ var Data = map[string]string{
 	"_":         "Поле {field} не прошло проверку",
 	"_validate": "Поле {field} не прошло проверку",
 	"_filter":   "Значение {field} некорректно",
+	"_context":  "Проверка прервана: %s",
+	"_compare":  "Значение {field} нельзя сравнить с полем %s: %s",
+	"anyOf":     "{field} должно пройти одну из проверок: %s",
 	// int
-	"min": "Минимальное значение {field} равно %d",
-	"max": "Максимальное значение {field} равно %d",
+	"min": "Минимальное значение {field} равно %v",
+	"max": "Максимальное значение {field} равно %v",
 	// type check: int
 	"isInt":  "{field} должно быть числом",
 	"isInt1": "{field} должно быть числом и не менее %d",         // has min check
-	"isInt2": "{field} должно быть числом и в диапазоне %d - %d", // has min, max check
+	"isInt2": "{field} должно быть числом и в диапазоне %v - %v", // has min, max check
 	"isInts": "{field} должно быть массивом чисел",
 	"isUint": "{field} должно быть положительным числом",
 	// type check: string
 	"isString":  "{field} должно быть строкой",
 	"isString1": "{field} должно быть строкой с минимальной длиной %d", // has min len check
 	// length
-	"minLength": "Длина {field} должна быть не меньше %d",
+	"minLength": "Длина {field} должна быть не меньше %v",
 	"maxLength": "Длина {field} должна быть не более %d",
 	// string length. calc rune
-	"stringLength":  "Длина {field} должна быть в диапазоне %d - %d",
+	"stringLength":  "Длина {field} должна быть в диапазоне %v - %v",
 	"stringLength1": "Минимальная длина {field} равна %d",
-	"stringLength2": "Длина {field} должна быть в диапазоне %d - %d",
+	"stringLength2": "Длина {field} должна быть в диапазоне %v - %v",
 
 	"isURL":     "{field} должно быть корректным URL адресом",
 	"isFullURL": "{field} должно быть корректным полным URL адресом",
@@ -46,13 +49,15 @@ var Data = map[string]string{
 	"isFile":  "{field} должно быть загруженным файлом",
 	"isImage": "{field} должно быть изображением",
 
-	"enum":  "{field} должно иметь одно из указанных значений: %v",
-	"range": "{field} должно быть в диапазоне %d - %d",
+	"enum":    "{field} должно иметь одно из указанных значений: %v",
+	"range":   "{field} должно быть в диапазоне %v - %v",
+	"between": "{field} должно быть в диапазоне %v - %v",
 	// int compare
-	"lt": "Значение {field} должно быть меньше %d",
-	"gt": "Значение {field} должно быть больше %d",
+	"lt": "Значение {field} должно быть меньше %v",
+	"gt": "Значение {field} должно быть больше %v",
 	// required
 	"required":           "{field} не может быть пустым",
+	"requiredKey":        "{field} обязательно должно присутствовать",
 	"requiredIf":         "{field} не может быть пустым, когда {args0} равно {args1end}",
 	"requiredUnless":     "{field} не может быть пустым, если {args0} не равно {args1end}",
 	"requiredWith":       "{field} не может быть пустым при наличии {values}",
@@ -17,18 +17,24 @@ func RegisterGlobal() {
 
 // Data zh-CN language messages
 var Data = map[string]string{
-	"_": "{field} 没有通过验证",
+	"_":        "{field} 没有通过验证",
+	"_context": "验证被中止: %s",
+	"_compare": "{field} 值无法与该字段 %s 比较: %s",
+	// rule group
+	"anyOf": "{field} 必须通过以下任意一个验证: %s",
 	// int
-	"min": "{field} 的最小值是 %d",
-	"max": "{field} 的最大值是 %d",
+	"min": "{field} 的最小值是 %v",
+	"max": "{field} 的最大值是 %v",
 	// Length
 	"minLength": "{field} 的最小长度是 %d",
 	"maxLength": "{field} 的最大长度是 %d",
 	// range
-	"enum":  "{field} 值必须在下列枚举中 %v",
-	"range": "{field} 值必须在此范围内 %d - %d",
+	"enum":    "{field} 值必须在下列枚举中 %v",
+	"range":   "{field} 值必须在此范围内 %v - %v",
+	"between": "{field} 值必须在此范围内 %v - %v",
 	// required
 	"required":           "{field} 是必填项",
+	"requiredKey":        "{field} 必须存在",
 	"requiredIf":         "当 %v 为 {args} 时 {field} 不能为空。",
 	"requiredUnless":     "当 %v 不为 {args} 时 {field} 不能为空。",
 	"requiredWith":       "当 {values} 存在时 {field} 不能为空。",
@@ -19,6 +19,14 @@ func TestRegister(t *testing.T) {
 
 	is.False(v.Validate())
 	is.Equal(v.Errors.One(), "age 的最大值是 1")
+
+	v = validate.Map(map[string]interface{}{
+		"age": 23,
+	})
+	Register(v)
+	v.StringRule("age", "between:0.5,9.5")
+	is.False(v.Validate())
+	is.Equal(v.Errors.One(), "age 值必须在此范围内 0.5 - 9.5")
 }
 
 func TestRegisterGlobal(t *testing.T) {
@@ -17,18 +17,24 @@ func RegisterGlobal() {
 
 // Data zh-TW language messages
 var Data = map[string]string{
-	"_": "{field} 沒有通過驗證",
+	"_":        "{field} 沒有通過驗證",
+	"_context": "驗證被中止: %s",
+	"_compare": "{field} 值無法與該字段 %s 比較: %s",
+	// rule group
+	"anyOf": "{field} 必須通過以下任意一個驗證: %s",
 	// int
-	"min": "{field} 的最小值是 %d",
-	"max": "{field} 的最大值是 %d",
+	"min": "{field} 的最小值是 %v",
+	"max": "{field} 的最大值是 %v",
 	// Length
 	"minLength": "{field} 的最小長度是 %d",
 	"maxLength": "{field} 的最大長度是 %d",
 	// range
-	"enum":  "{field} 值必須在下列枚舉中 %v",
-	"range": "{field} 值必須在此範圍內 %d - %d",
+	"enum":    "{field} 值必須在下列枚舉中 %v",
+	"range":   "{field} 值必須在此範圍內 %v - %v",
+	"between": "{field} 值必須在此範圍內 %v - %v",
 	// required
 	"required":           "{field} 是必填項",
+	"requiredKey":        "{field} 必須存在",
 	"requiredIf":         "當 %v 為 {args} 時 {field} 不能為空。",
 	"requiredUnless":     "當 %v 不為 {args} 時 {field} 不能為空。",
 	"requiredWith":       "當 {values} 存在時 {field} 不能為空。",
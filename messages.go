@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -44,9 +45,27 @@ func (es Errors) Add(field, validator, message string) {
 	}
 }
 
-// One returns an random error message text
+// One returns an error message text. it is deterministic, will return
+// the first error of the first field on sorted by name.
+//
+// NOTICE: the map can't keep the order of occurred, use v.FieldErrors().One()
+// to get the first error in the order of the rules and fields were evaluated.
 func (es Errors) One() string {
-	return es.Random()
+	if len(es) == 0 {
+		return ""
+	}
+	return es[es.Fields()[0]].One()
+}
+
+// Fields get all error field names, sorted by name.
+func (es Errors) Fields() []string {
+	fields := make([]string, 0, len(es))
+	for field := range es {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+	return fields
 }
 
 // Random returns an random error message text
@@ -74,11 +93,11 @@ func (es Errors) Error() string {
 	return es.String()
 }
 
-// String errors to string
+// String errors to string. the fields are sorted by name.
 func (es Errors) String() string {
 	buf := new(bytes.Buffer)
-	for field, fe := range es {
-		buf.WriteString(fmt.Sprintf("%s:\n%s\n", field, fe.String()))
+	for _, field := range es.Fields() {
+		buf.WriteString(fmt.Sprintf("%s:\n%s\n", field, es[field].String()))
 	}
 
 	return strings.TrimSpace(buf.String())
@@ -95,7 +114,8 @@ func (es Errors) Field(field string) map[string]string {
 	return es[field]
 }
 
-// FieldOne returns an error message for the field
+// FieldOne returns an error message for the field, the validator is first one on sorted by name.
+// use v.FieldErrors().FieldOne() to get the first error of the field in the order of occurred.
 func (es Errors) FieldOne(field string) string {
 	if fe, ok := es[field]; ok {
 		return fe.One()
@@ -104,6 +124,158 @@ func (es Errors) FieldOne(field string) string {
 	return ""
 }
 
+/*************************************************************
+ * Field Errors
+ *************************************************************/
+
+// FieldError is a structured validate error of a field.
+type FieldError struct {
+	// Field the normalized field path. eg: "name", "items.0.price"
+	Field string
+	// Validator name. eg: "required", "minLen"
+	Validator string
+	// Args the validator arguments. eg: [6] for "minLen:6"
+	Args []interface{}
+	// Value the rejected field value
+	Value interface{}
+	// Message the translated error message
+	Message string
+	// Err the original error, if has. eg: context.Canceled
+	Err error
+	// Pos the source position of the field value, if the data source is a PositionFace.
+	Pos *Position
+}
+
+// Error string get
+func (fe *FieldError) Error() string {
+	return fe.Message
+}
+
+// Unwrap the original error
+func (fe *FieldError) Unwrap() error {
+	return fe.Err
+}
+
+// FieldErrors list, the order is same as the errors occurred.
+// Can get it from the validate error by errors.As()
+//
+// Usage:
+// 	var fes validate.FieldErrors
+// 	if errors.As(v.Err(), &fes) {
+// 		fmt.Println(fes[0].Field, fes[0].Validator)
+// 	}
+type FieldErrors []*FieldError
+
+// Empty no error
+func (fes FieldErrors) Empty() bool {
+	return len(fes) == 0
+}
+
+// Error string get
+func (fes FieldErrors) Error() string {
+	return fes.String()
+}
+
+// String errors to string, keep the order of occurred.
+func (fes FieldErrors) String() string {
+	buf := new(bytes.Buffer)
+	for _, field := range fes.Fields() {
+		buf.WriteString(field + ":\n")
+		for _, fe := range fes.Field(field) {
+			buf.WriteString(" " + fe.Validator + ": " + fe.Message + "\n")
+		}
+	}
+
+	return strings.TrimSpace(buf.String())
+}
+
+// Fields get all error field names, keep the order of occurred.
+func (fes FieldErrors) Fields() []string {
+	var fields []string
+	exists := make(map[string]bool, len(fes))
+	for _, fe := range fes {
+		if !exists[fe.Field] {
+			exists[fe.Field] = true
+			fields = append(fields, fe.Field)
+		}
+	}
+	return fields
+}
+
+// Each iterate the errors by the order of occurred, stop on fn returns false.
+func (fes FieldErrors) Each(fn func(fe *FieldError) bool) {
+	for _, fe := range fes {
+		if !fn(fe) {
+			break
+		}
+	}
+}
+
+// As support errors.As() the first *FieldError, or the original error of the field errors. eg: *RuleParseError
+func (fes FieldErrors) As(target interface{}) bool {
+	if fe, ok := target.(**FieldError); ok {
+		if len(fes) > 0 {
+			*fe = fes[0]
+			return true
+		}
+		return false
+	}
+
+	for _, fe := range fes {
+		if fe.Err != nil && errorAs(fe.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Is support errors.Is() the original error of each field error. eg: context.Canceled
+func (fes FieldErrors) Is(target error) bool {
+	for _, fe := range fes {
+		if fe.Err != nil && errorIs(fe.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// One returns the first error message text
+func (fes FieldErrors) One() string {
+	if len(fes) > 0 {
+		return fes[0].Message
+	}
+	return ""
+}
+
+// FieldOne returns the first error message of the field
+func (fes FieldErrors) FieldOne(field string) string {
+	for _, fe := range fes {
+		if fe.Field == field {
+			return fe.Message
+		}
+	}
+	return ""
+}
+
+// Field get all errors of the field
+func (fes FieldErrors) Field(field string) (list FieldErrors) {
+	for _, fe := range fes {
+		if fe.Field == field {
+			list = append(list, fe)
+		}
+	}
+	return
+}
+
+// Errors convert to the Errors map
+func (fes FieldErrors) Errors() Errors {
+	es := make(Errors, len(fes))
+	for _, fe := range fes {
+		es.Add(fe.Field, fe.Validator, fe.Message)
+	}
+	return es
+}
+
 /*************************************************************
  * Validator error messages
  *************************************************************/
@@ -112,17 +284,27 @@ func (es Errors) FieldOne(field string) string {
 var builtinMessages = map[string]string{
 	"_": "{field}" + defaultErrMsg, // default message
 	// builtin
-	"_validate": "{field} did not pass validate", // default validate message
-	"_filter":   "{field} data is invalid",       // data filter error
+	"_validate": "{field} did not pass validate",                      // default validate message
+	"_filter":   "{field} data is invalid",                            // data filter error
+	"_context":  "validate is aborted: %s",                            // context done error
+	"_compare":  "{field} value cannot compare with the field %s: %s", // field compare error
+	// rule group
+	"anyOf": "{field} must pass any of the validators: %s",
 	// int value
-	"min": "{field} min value is %d",
-	"max": "{field} max value is %d",
+	"min": "{field} min value is %v",
+	"max": "{field} max value is %v",
 	// type check: int
 	"isInt":  "{field} value must be an integer",
 	"isInt1": "{field} value must be an integer and mix value is %d",      // has min check
 	"isInt2": "{field} value must be an integer and in the range %d - %d", // has min, max check
 	"isInts": "{field} value must be an int slice",
 	"isUint": "{field} value must be an unsigned integer(>= 0)",
+	// type check: decimal
+	"isDecimal":  "{field} value must be a decimal number",
+	"isDecimal1": "{field} value must be a decimal number with at most %d digits",
+	"isDecimal2": "{field} value must be a decimal number with at most %d digits and %d decimal places",
+	// type check: JSON Schema type
+	"isJSONType": "{field} value must be of the JSON type {values}",
 	// type check: string
 	"isString":  "{field} value must be a string",
 	"isString1": "{field} value must be a string and min length is %d", // has min len check
@@ -140,13 +322,15 @@ var builtinMessages = map[string]string{
 	"isFile":  "{field} must be an uploaded file",
 	"isImage": "{field} must be an uploaded image file",
 
-	"enum":  "{field} value must be in the enum %v",
-	"range": "{field} value must be in the range %d - %d",
+	"enum":    "{field} value must be in the enum %v",
+	"range":   "{field} value must be in the range %v - %v",
+	"between": "{field} value must be in the range %v - %v",
 	// int compare
-	"lt": "{field} value should less than %d",
-	"gt": "{field} value should greater the %d",
+	"lt": "{field} value should less than %v",
+	"gt": "{field} value should greater the %v",
 	// required
 	"required":           "{field} is required and not empty",
+	"requiredKey":        "{field} is required",
 	"requiredIf":         "{field} is required when {args0} is {args1end}",
 	"requiredUnless":     "{field} field is required unless {args0} is in {args1end}",
 	"requiredWith":       "{field} field is required when {values} is present",
@@ -183,6 +367,13 @@ var builtinMessages = map[string]string{
 	"ltDate":  "{field} value should be before %s",
 	"gteDate": "{field} value should be after or equal to %s",
 	"lteDate": "{field} value should be before or equal to %s",
+	// time, duration
+	"after":       "{field} value should be after %s",
+	"before":      "{field} value should be before %s",
+	"withinLast":  "{field} value should be within the last %s",
+	"isWeekday":   "{field} value should be on the weekday",
+	"minDuration": "{field} duration should be at least %s",
+	"maxDuration": "{field} duration should be at most %s",
 	// check char
 	"hasWhitespace":  "{field} value should contains spaces",
 	"ascii":          "{field} value should be an ASCII string",
@@ -225,21 +416,17 @@ var builtinMessages = map[string]string{
 
 // AddGlobalMessages add global builtin messages
 func AddGlobalMessages(mp map[string]string) {
-	for name, msg := range mp {
-		builtinMessages[name] = msg
-	}
+	defRegistry.AddMessages(mp)
 }
 
 // AddBuiltinMessages alias of the AddGlobalMessages()
 func AddBuiltinMessages(mp map[string]string) {
-	for name, msg := range mp {
-		builtinMessages[name] = msg
-	}
+	defRegistry.AddMessages(mp)
 }
 
-// BuiltinMessages get builtin messages
+// BuiltinMessages get builtin messages. returns a copy of the global messages.
 func BuiltinMessages() map[string]string {
-	return builtinMessages
+	return defRegistry.Messages()
 }
 
 /*************************************************************
@@ -248,35 +435,47 @@ func BuiltinMessages() map[string]string {
 
 // Translator definition
 type Translator struct {
+	// the registry for get default messages and validator alias names
+	reg *Registry
 	// language string TODO
 	// field map {"field name": "display name"}
 	fieldMap map[string]string
 	// message data map
 	messages map[string]string
+	// the data source provides the names of the not translated fields. eg: EnvData
+	namer fieldNamer
 }
 
 // NewTranslator instance
 func NewTranslator() *Translator {
-	newMessages := make(map[string]string)
-	for k, v := range builtinMessages {
-		newMessages[k] = v
-	}
+	return newTranslator(defRegistry)
+}
 
+func newTranslator(reg *Registry) *Translator {
 	return &Translator{
+		reg:      reg,
 		fieldMap: make(map[string]string),
-		messages: newMessages,
+		messages: reg.Messages(),
 	}
 }
 
 // Reset translator to default
 func (t *Translator) Reset() {
-	newMessages := make(map[string]string)
-	for k, v := range builtinMessages {
-		newMessages[k] = v
+	t.messages = t.reg.Messages()
+	t.fieldMap = make(map[string]string)
+}
+
+// clone a new translator
+func (t *Translator) clone() *Translator {
+	nt := &Translator{
+		reg:      t.reg,
+		fieldMap: make(map[string]string, len(t.fieldMap)),
+		messages: make(map[string]string, len(t.messages)),
 	}
 
-	t.messages = newMessages
-	t.fieldMap = make(map[string]string)
+	nt.AddFieldMap(t.fieldMap)
+	nt.AddMessages(t.messages)
+	return nt
 }
 
 // FieldMap data get
@@ -318,35 +517,48 @@ func (t *Translator) HasMessage(key string) bool {
 
 // Message get by validator name and field name.
 func (t *Translator) Message(validator, field string, args ...interface{}) (msg string) {
+	return t.fieldMessage(validator, field, field, args...)
+}
+
+// fieldMessage get message for the field.
+// pattern is the field name in the rule, maybe contains wildcard. eg: "items.*.price"
+func (t *Translator) fieldMessage(validator, field, pattern string, args ...interface{}) (msg string) {
 	var ok bool
-	msg, ok = t.format(validator, field, args...)
+	msg, ok = t.format(validator, field, pattern, args...)
 	if ok {
 		return
 	}
 
 	// try check "validator" is an alias name
-	if rName, has := validatorAliases[validator]; has {
-		msg, ok = t.format(rName, field, args...)
+	if rName, has := t.reg.realName(validator); has {
+		msg, ok = t.format(rName, field, pattern, args...)
 		if ok {
 			return
 		}
 	}
 
 	// not found, fallback - use default error message
-	if !ok {
-		// get field display name.
-		if trName, ok := t.fieldMap[field]; ok {
-			field = trName
+	return t.fieldName(field, pattern) + defaultErrMsg
+}
+
+// get field display name.
+func (t *Translator) fieldName(field, pattern string) string {
+	for _, name := range fieldAndPattern(field, pattern) {
+		if trName, ok := t.fieldMap[name]; ok {
+			return trName
 		}
-		return field + defaultErrMsg
 	}
-	return
+
+	if t.namer != nil {
+		return t.namer.fieldName(field)
+	}
+	return field
 }
 
 // format message for the validator
-func (t *Translator) format(validator, field string, args ...interface{}) (string, bool) {
+func (t *Translator) format(validator, field, pattern string, args ...interface{}) (string, bool) {
 	argLen := len(args)
-	errMsg := t.findMessage(validator, field, argLen)
+	errMsg := t.findMessage(validator, field, pattern, argLen)
 	if errMsg == "" {
 		return "", false
 	}
@@ -362,9 +574,7 @@ func (t *Translator) format(validator, field string, args ...interface{}) (strin
 	}
 
 	// get field display name.
-	if trName, ok := t.fieldMap[field]; ok {
-		field = trName
-	}
+	field = t.fieldName(field, pattern)
 
 	if argLen > 0 {
 		// whether need call fmt.Sprintf
@@ -392,31 +602,32 @@ func (t *Translator) format(validator, field string, args ...interface{}) (strin
 	return errMsg, true
 }
 
-func (t *Translator) findMessage(validator, field string, argLen int) string {
-	// - format1: "field name" + "." + "validator name".
-	// eg: "age.isInt" "name.required"
-	fullKey := field + "." + validator
+func (t *Translator) findMessage(validator, field, pattern string, argLen int) string {
+	names := fieldAndPattern(field, pattern)
 
 	// validator support variadic params. eg: isInt1 isInt2
 	if argLen > 0 {
 		lenStr := strconv.Itoa(argLen)
 
 		// eg: "age.isInt1" "age.isInt2"
-		newFullKey := fullKey + lenStr
-		if msg, ok := t.messages[newFullKey]; ok {
-			return msg
+		for _, name := range names {
+			if msg, ok := t.messages[name+"."+validator+lenStr]; ok {
+				return msg
+			}
 		}
 
 		// eg: "isInt1" "isInt2"
-		newNameKey := validator + lenStr
-		if msg, ok := t.messages[newNameKey]; ok {
+		if msg, ok := t.messages[validator+lenStr]; ok {
 			return msg
 		}
 	}
 
-	// use fullKey find
-	if msg, ok := t.messages[fullKey]; ok {
-		return msg
+	// - format1: "field name" + "." + "validator name".
+	// eg: "age.isInt" "name.required"
+	for _, name := range names {
+		if msg, ok := t.messages[name+"."+validator]; ok {
+			return msg
+		}
 	}
 
 	// only validator name. "required"
@@ -425,3 +636,11 @@ func (t *Translator) findMessage(validator, field string, argLen int) string {
 	}
 	return ""
 }
+
+// fieldAndPattern returns the field and the wildcard pattern(if not same) for find messages.
+func fieldAndPattern(field, pattern string) []string {
+	if pattern == "" || pattern == field {
+		return []string{field}
+	}
+	return []string{field, pattern}
+}
@@ -45,6 +45,103 @@ func TestErrorsBasic(t *testing.T) {
 
 	es.Add("test", "v1", "err msg1")
 	assert.Len(t, es.Field("test"), 2)
+
+	// deterministic
+	es.Add("a", "v2", "err msg3")
+	assert.Equal(t, []string{"a", "test", "test2"}, es.Fields())
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "err msg3", es.One())
+		assert.Equal(t, "err msg0", es.FieldOne("test"))
+		assert.Equal(t, "a:\n v2: err msg3\ntest:\n v0: err msg0\n v1: err msg1\ntest2:\n v1: err msg2", es.String())
+	}
+}
+
+func TestFieldErrors_order(t *testing.T) {
+	is := assert.New(t)
+
+	v := Map(M{"name": "abc", "age": 10, "city": ""})
+	v.StopOnError = false
+	v.StringRules(MS{
+		"name": "minLen:6",
+		"city": "required",
+		"age":  "min:18",
+	})
+	v.AddRule("name", "maxLen", 2)
+
+	is.False(v.Validate())
+	// rules added by StringRules are sorted by field name.
+	is.Equal([]string{"age", "city", "name"}, v.FieldErrors().Fields())
+	is.Equal("age min value is 18", v.FieldErrors().One())
+
+	var validators []string
+	v.FieldErrors().Each(func(fe *FieldError) bool {
+		validators = append(validators, fe.Validator)
+		return fe.Field != "name"
+	})
+	is.Equal([]string{"min", "required", "minLen"}, validators)
+	is.Equal("name:\n minLen: name min length is 6\n maxLen: name max length is 2", v.FieldErrors().Field("name").String())
+
+	// the rule order is not same as the alphabetical order
+	v = Map(M{"name": "abc", "age": 10})
+	v.StopOnError = false
+	v.AddRule("name", "minLen", 6)
+	v.AddRule("name", "email")
+	v.AddRule("age", "min", 18)
+
+	is.False(v.Validate())
+	is.Equal("name min length is 6", v.FieldErrors().One())
+	is.Equal("name min length is 6", v.FieldErrors().FieldOne("name"))
+	is.Equal("age min value is 18", v.FieldErrors().FieldOne("age"))
+	is.Equal("", v.FieldErrors().FieldOne("city"))
+	is.Equal("name min length is 6", newResult(v).FieldErrors().One())
+	// the map is sorted by name
+	is.Equal("age min value is 18", v.Errors.One())
+	is.Equal("name value is invalid mail", v.Errors.FieldOne("name"))
+}
+
+func TestFieldErrors(t *testing.T) {
+	is := assert.New(t)
+
+	v := Map(M{"name": "abc", "items": []interface{}{M{"price": 0}}})
+	v.StopOnError = false
+	v.AddRule("name", "minLen", 6)
+	v.AddRule("items.*.price", "required")
+
+	is.NoError(v.Err())
+	is.False(v.Validate())
+
+	fes := v.FieldErrors()
+	is.Len(fes, 2)
+	is.Equal("name", fes[0].Field)
+	is.Equal("minLen", fes[0].Validator)
+	is.Equal([]interface{}{6}, fes[0].Args)
+	is.Equal("abc", fes[0].Value)
+	is.Equal("name min length is 6", fes[0].Message)
+	is.Equal("name min length is 6", fes.One())
+	is.Equal("items.0.price", fes[1].Field)
+	is.Len(fes.Field("items.0.price"), 1)
+
+	// compatible view
+	is.Equal(v.Errors, fes.Errors())
+	is.Equal("name:\n minLen: name min length is 6\nitems.0.price:\n required: items.0.price is required and not empty", v.Err().Error())
+	is.Equal([]string{"name", "items.0.price"}, fes.Fields())
+
+	// errors.As
+	var list FieldErrors
+	is.True(errorAs(v.Err(), &list))
+	is.Len(list, 2)
+
+	var fe *FieldError
+	is.True(errorAs(v.Err(), &fe))
+	is.Equal("minLen", fe.Validator)
+
+	// custom added error
+	v.AddError("age", "custom", "age is invalid")
+	is.Equal("custom", v.FieldErrors()[2].Validator)
+
+	v.ResetResult()
+	is.Nil(v.FieldErrors())
+	is.NoError(v.Err())
 }
 
 func TestTranslatorBasic(t *testing.T) {
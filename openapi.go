@@ -0,0 +1,617 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/*************************************************************
+ * OpenAPI components.schemas export
+ *************************************************************/
+
+// OpenAPISchemas export the struct tag rules as the OpenAPI 3.1 "components.schemas" section.
+// the key is the struct type name, the custom error messages of the message tag are
+// exported by the JSONSchemaMessagesKeyword.
+//
+// Usage:
+// 	schemas, err := validate.OpenAPISchemas(&UserForm{}, &LoginForm{})
+// 	doc := validate.M{
+// 		"openapi":    "3.1.0",
+// 		"components": validate.M{"schemas": schemas},
+// 	}
+func OpenAPISchemas(structs ...interface{}) (M, error) {
+	schemas := make(M, len(structs))
+	for _, s := range structs {
+		doc, err := StructJSONSchema(s)
+		if err != nil {
+			return nil, err
+		}
+
+		name := removeTypePtr(reflect.TypeOf(s)).Name()
+		if name == "" {
+			return nil, errors.New("validate: the OpenAPI schema struct must be a named type")
+		}
+		if _, ok := schemas[name]; ok {
+			return nil, fmt.Errorf("validate: the OpenAPI schema name '%s' is duplicated", name)
+		}
+
+		// the "$schema" is defined by the "jsonSchemaDialect" of the OpenAPI document.
+		delete(doc, "$schema")
+		schemas[name] = doc
+	}
+	return schemas, nil
+}
+
+/*************************************************************
+ * OpenAPI request validator
+ *************************************************************/
+
+// OpenAPIValidator validate the http.Request by the parameters and request body schema
+// of an OpenAPI 3 operation. it is immutable after created, can be shared by all goroutines.
+//
+// the data is validated under the field paths: "path.NAME", "query.NAME", "header.NAME",
+// "cookie.NAME" and "body". eg: "query.page", "body.user.name"
+//
+// Usage:
+// 	ov, err := validate.NewOpenAPIValidator(spec, "POST", "/users/{id}")
+//
+// 	// in the handler
+// 	res := ov.Validate(r, map[string]string{"id": id})
+// 	if res.IsFail() {
+// 		fmt.Println(res.Errors)
+// 	}
+type OpenAPIValidator struct {
+	params []*openAPIParam
+	// the schema for the request without body.
+	noBody *Schema
+	// media type to the schema with the body rules. eg: "application/json"
+	bodies map[string]*Schema
+	// the JSON schema of the form body properties, for convert the form values.
+	formProps map[string]map[string]interface{}
+	// Unsupported the JSON pointers of the keywords cannot be mapped to the validators.
+	// eg: ["/paths/~1users/post/requestBody/content/application~1json/schema/uniqueItems"]
+	Unsupported []string
+}
+
+// openAPIParam is an operation parameter
+type openAPIParam struct {
+	// JSON pointer of the parameter
+	ptr  string
+	name string
+	// in: path, query, header, cookie
+	in       string
+	required bool
+	explode  bool
+	schema   map[string]interface{}
+}
+
+// the locations of the parameters
+var openAPIParamIn = map[string]bool{"path": true, "query": true, "header": true, "cookie": true}
+
+// the keywords only used by the OpenAPI, they don't affect the validation.
+var openAPIAnnotations = map[string]bool{
+	"example":       true,
+	"discriminator": true,
+	"xml":           true,
+	"externalDocs":  true,
+}
+
+// the max depth of the resolving "$ref"
+const openAPIMaxRefDepth = 32
+
+var errOpenAPIRefDepth = errors.New("validate: the OpenAPI \"$ref\" is too deep or recursive")
+
+// NewOpenAPIValidator create an OpenAPIValidator for the operation from the OpenAPI 3 document(JSON).
+// the local "$ref" like "#/components/schemas/User" are resolved.
+func NewOpenAPIValidator(spec []byte, method, path string) (*OpenAPIValidator, error) {
+	var root interface{}
+	// keep the precision of the numbers
+	if err := unmarshalJSON(spec, &root, true); err != nil {
+		return nil, err
+	}
+
+	doc, ok := root.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("validate: the OpenAPI document must be an object")
+	}
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	item, ok := paths[path].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("validate: the OpenAPI path '%s' is not found", path)
+	}
+
+	method = strings.ToLower(method)
+	if _, ok = item[method].(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("validate: the OpenAPI operation '%s %s' is not found", method, path)
+	}
+
+	// resolve the "$ref" of the parameters and operation.
+	r := &openAPIResolver{doc: doc}
+	pathParams, err := r.resolve(item["parameters"], 0)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := r.resolve(item[method], 0)
+	if err != nil {
+		return nil, err
+	}
+	op := resolved.(map[string]interface{})
+
+	ptr := "/paths/" + escapeJSONPointer(path)
+	ov := &OpenAPIValidator{
+		bodies:    make(map[string]*Schema),
+		formProps: make(map[string]map[string]interface{}),
+	}
+
+	// the operation parameters override the path item parameters.
+	indexes := make(map[string]int)
+	lists := []struct {
+		ptr  string
+		list interface{}
+	}{
+		{ptr + "/parameters", pathParams},
+		{ptr + "/" + method + "/parameters", op["parameters"]},
+	}
+
+	for _, pl := range lists {
+		items, _ := pl.list.([]interface{})
+		for i, val := range items {
+			param, err := parseOpenAPIParam(pl.ptr+"/"+strconv.Itoa(i), val)
+			if err != nil {
+				return nil, err
+			}
+
+			key := param.in + "." + param.name
+			if idx, ok := indexes[key]; ok {
+				ov.params[idx] = param
+			} else {
+				indexes[key] = len(ov.params)
+				ov.params = append(ov.params, param)
+			}
+		}
+	}
+
+	params := newJSONSchemaParser()
+	for _, param := range ov.params {
+		field := param.in + "." + param.name
+		params.parse(field, param.ptr+"/schema", param.schema)
+		if param.required {
+			params.required[field] = true
+		}
+	}
+
+	// the request body
+	body, _ := op["requestBody"].(map[string]interface{})
+	newBodyParser := func() *jsonSchemaParser {
+		bp := newJSONSchemaParser()
+		if body["required"] == true {
+			bp.required["body"] = true
+		}
+		return bp
+	}
+
+	ov.noBody = ov.compile(params, newBodyParser())
+	content, _ := body["content"].(map[string]interface{})
+	for _, mediaType := range sortedMapKeys(content) {
+		mt, _ := content[mediaType].(map[string]interface{})
+		sm, _ := mt["schema"].(map[string]interface{})
+		if sm == nil {
+			sm = map[string]interface{}{}
+		}
+
+		bp := newBodyParser()
+		bp.parse("body", ptr+"/"+method+"/requestBody/content/"+escapeJSONPointer(mediaType)+"/schema", sm)
+		ov.bodies[strings.ToLower(mediaType)] = ov.compile(params, bp)
+
+		if props, ok := sm["properties"].(map[string]interface{}); ok {
+			ov.formProps[strings.ToLower(mediaType)] = props
+		}
+	}
+
+	ov.Unsupported = append(ov.Unsupported, params.unsupported...)
+	return ov, nil
+}
+
+// parse the parameter object
+func parseOpenAPIParam(ptr string, val interface{}) (*openAPIParam, error) {
+	pm, _ := val.(map[string]interface{})
+	name, _ := pm["name"].(string)
+	in, _ := pm["in"].(string)
+	if name == "" || !openAPIParamIn[in] || strings.ContainsAny(name, ".*") {
+		return nil, fmt.Errorf("validate: the OpenAPI parameter '%s' is invalid", ptr)
+	}
+
+	param := &openAPIParam{
+		ptr:  ptr,
+		name: name,
+		in:   in,
+		// the path parameters are always required.
+		required: pm["required"] == true || in == "path",
+		explode:  in == "query" || in == "cookie",
+	}
+
+	if explode, ok := pm["explode"].(bool); ok {
+		param.explode = explode
+	}
+
+	param.schema, _ = pm["schema"].(map[string]interface{})
+	if param.schema == nil {
+		param.schema = map[string]interface{}{}
+	}
+	return param, nil
+}
+
+// compile the parameters and body rules to a Schema.
+func (ov *OpenAPIValidator) compile(params, body *jsonSchemaParser) *Schema {
+	ps, bs := params.result(), body.result()
+	ov.Unsupported = append(ov.Unsupported, bs.Unsupported...)
+
+	return NewSchema(func(v *Validation) {
+		ps.Apply(v)
+		bs.Apply(v)
+	})
+}
+
+// Validate the request parameters and body. the pathParams is the values of the path parameters,
+// they are parsed by the router.
+//
+// the string values of the parameters and form body are converted by the schema type.
+// eg: "?page=2" -> {"query": {"page": 2}}
+func (ov *OpenAPIValidator) Validate(r *http.Request, pathParams map[string]string) *Result {
+	data := M{}
+	for _, param := range ov.params {
+		var vals []string
+		switch param.in {
+		case "path":
+			if val, ok := pathParams[param.name]; ok {
+				vals = []string{val}
+			}
+		case "query":
+			vals = r.URL.Query()[param.name]
+		case "header":
+			vals = r.Header[http.CanonicalHeaderKey(param.name)]
+		case "cookie":
+			if c, err := r.Cookie(param.name); err == nil {
+				vals = []string{c.Value}
+			}
+		}
+
+		if len(vals) == 0 {
+			continue
+		}
+
+		group, _ := data[param.in].(M)
+		if group == nil {
+			group = M{}
+			data[param.in] = group
+		}
+		group[param.name] = param.convert(vals)
+	}
+
+	schema := ov.noBody
+	var bodyErr error
+	if hasRequestBody(r) {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		mediaType = strings.ToLower(mediaType)
+		if s, key := ov.bodySchema(mediaType); s != nil {
+			schema = s
+			data["body"], bodyErr = ov.readBody(r, key)
+		} else if len(ov.bodies) > 0 {
+			bodyErr = fmt.Errorf("the request content type '%s' is not supported", mediaType)
+		}
+	}
+
+	v := schema.newValidation(FromMap(data))
+	if bodyErr != nil {
+		v.WithError(bodyErr)
+	}
+
+	v.Validate()
+	return newResult(v)
+}
+
+// bodySchema find the schema for the media type. the "type/*" and "*/*" can be matched.
+func (ov *OpenAPIValidator) bodySchema(mediaType string) (*Schema, string) {
+	if mediaType == "" {
+		return nil, ""
+	}
+
+	keys := []string{mediaType, "*/*"}
+	if idx := strings.IndexByte(mediaType, '/'); idx > 0 {
+		keys = []string{mediaType, mediaType[:idx] + "/*", "*/*"}
+	}
+
+	for _, key := range keys {
+		if s, ok := ov.bodies[key]; ok {
+			return s, key
+		}
+	}
+	return nil, ""
+}
+
+// read the request body by the FromRequest data sources.
+func (ov *OpenAPIValidator) readBody(r *http.Request, mediaType string) (interface{}, error) {
+	d, err := FromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch td := d.(type) {
+	case *MapData:
+		return td.Map, nil
+	case *FormData:
+		// the FromRequest merged the queries to the form, only use the post form.
+		props := ov.formProps[mediaType]
+		body := make(map[string]interface{}, len(r.PostForm)+len(td.Files))
+		for key, vals := range r.PostForm {
+			prop, _ := props[key].(map[string]interface{})
+			body[key] = convertOpenAPIValues(prop, vals, true)
+		}
+
+		for key, file := range td.Files {
+			body[key] = file
+		}
+		return body, nil
+	}
+	return nil, ErrEmptyData
+}
+
+// convert the parameter string values by the schema type.
+func (p *openAPIParam) convert(vals []string) interface{} {
+	return convertOpenAPIValues(p.schema, vals, p.explode)
+}
+
+// convert the string values by the schema type. the array items are split by comma if not explode.
+func convertOpenAPIValues(schema map[string]interface{}, vals []string, explode bool) interface{} {
+	types, _ := jsonStrings(schema["type"])
+	if !strSliceHas(types, "array") {
+		return convertOpenAPIValue(types, vals[0])
+	}
+
+	if !explode && len(vals) == 1 {
+		vals = strings.Split(vals[0], ",")
+	}
+
+	items, _ := schema["items"].(map[string]interface{})
+	itemTypes, _ := jsonStrings(items["type"])
+
+	list := make([]interface{}, len(vals))
+	for i, val := range vals {
+		list[i] = convertOpenAPIValue(itemTypes, val)
+	}
+	return list
+}
+
+// convert the string value to the number or bool. keep the string on failed,
+// then the "isJSONType" validator will report the error.
+func convertOpenAPIValue(types []string, val string) interface{} {
+	if (strSliceHas(types, "integer") || strSliceHas(types, "number")) && rxJSONNumber.MatchString(val) {
+		return json.Number(val)
+	}
+
+	if strSliceHas(types, "boolean") {
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return val
+}
+
+// the request has the body content
+func hasRequestBody(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+}
+
+func strSliceHas(ss []string, s string) bool {
+	for _, item := range ss {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// openAPIResolver resolve the local "$ref" of the OpenAPI document.
+type openAPIResolver struct {
+	doc map[string]interface{}
+}
+
+// resolve the "$ref" in the val deeply, the "schema" objects are resolved by the resolveSchema().
+func (r *openAPIResolver) resolve(val interface{}, depth int) (interface{}, error) {
+	return r.walk(val, depth, func(key string, item interface{}) (interface{}, error) {
+		if key == "schema" {
+			return r.resolveSchema(item, depth)
+		}
+		return r.resolve(item, depth)
+	})
+}
+
+// the keywords of the schema object, the values are the sub schemas.
+var (
+	// the map of the sub schemas. eg: {"properties": {"name": {...}}}
+	openAPISchemaMaps = map[string]bool{
+		"properties":        true,
+		"patternProperties": true,
+		"definitions":       true,
+		"$defs":             true,
+		"dependentSchemas":  true,
+	}
+	// the sub schema, or the list of the sub schemas. eg: {"items": {...}}, {"anyOf": [{...}]}
+	openAPISchemaItems = map[string]bool{
+		"items":                true,
+		"prefixItems":          true,
+		"additionalItems":      true,
+		"additionalProperties": true,
+		"propertyNames":        true,
+		"contains":             true,
+		"not":                  true,
+		"if":                   true,
+		"then":                 true,
+		"else":                 true,
+		"allOf":                true,
+		"anyOf":                true,
+		"oneOf":                true,
+	}
+)
+
+// resolve the "$ref" of the schema object deeply, and convert the OpenAPI 3.0 keywords to the JSON Schema.
+// only the sub schemas are walked, the names of the properties and the values like "enum" are kept.
+func (r *openAPIResolver) resolveSchema(val interface{}, depth int) (interface{}, error) {
+	if depth > openAPIMaxRefDepth {
+		return nil, errOpenAPIRefDepth
+	}
+
+	tv, ok := val.(map[string]interface{})
+	if !ok {
+		return val, nil
+	}
+
+	if ref, ok := tv["$ref"].(string); ok {
+		target, err := r.lookup(ref)
+		if err != nil {
+			return nil, err
+		}
+		return r.resolveSchema(target, depth+1)
+	}
+
+	m := make(map[string]interface{}, len(tv))
+	for key, item := range tv {
+		if openAPIAnnotations[key] || key == "nullable" {
+			continue
+		}
+
+		var err error
+		switch {
+		case openAPISchemaMaps[key]:
+			item, err = r.walk(item, depth, func(_ string, sub interface{}) (interface{}, error) {
+				return r.resolveSchema(sub, depth)
+			})
+		case openAPISchemaItems[key]:
+			if list, ok := item.([]interface{}); ok {
+				item, err = r.walk(list, depth, func(_ string, sub interface{}) (interface{}, error) {
+					return r.resolveSchema(sub, depth)
+				})
+			} else {
+				item, err = r.resolveSchema(item, depth)
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		m[key] = item
+	}
+
+	// the uploaded file. it is a *multipart.FileHeader on validating.
+	if m["format"] == "binary" {
+		delete(m, "type")
+		delete(m, "format")
+	}
+
+	convertOpenAPIExclusive(m, "minimum", "exclusiveMinimum")
+	convertOpenAPIExclusive(m, "maximum", "exclusiveMaximum")
+	if tv["nullable"] == true {
+		convertOpenAPINullable(m)
+	}
+	return m, nil
+}
+
+// walk the list or map val, the "$ref" of the map is resolved.
+// the fn resolve each item, the key is empty for the list items.
+func (r *openAPIResolver) walk(val interface{}, depth int, fn func(key string, item interface{}) (interface{}, error)) (interface{}, error) {
+	if depth > openAPIMaxRefDepth {
+		return nil, errOpenAPIRefDepth
+	}
+
+	switch tv := val.(type) {
+	case []interface{}:
+		list := make([]interface{}, len(tv))
+		for i, item := range tv {
+			resolved, err := fn("", item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = resolved
+		}
+		return list, nil
+	case map[string]interface{}:
+		if ref, ok := tv["$ref"].(string); ok {
+			target, err := r.lookup(ref)
+			if err != nil {
+				return nil, err
+			}
+			return r.walk(target, depth+1, fn)
+		}
+
+		m := make(map[string]interface{}, len(tv))
+		for key, item := range tv {
+			resolved, err := fn(key, item)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = resolved
+		}
+		return m, nil
+	}
+	return val, nil
+}
+
+// lookup the local JSON pointer. eg: "#/components/schemas/User"
+func (r *openAPIResolver) lookup(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("validate: the OpenAPI \"$ref\" '%s' is not supported, only local refs", ref)
+	}
+
+	var cur interface{} = r.doc
+	for _, name := range strings.Split(ref[2:], "/") {
+		name = strings.Replace(strings.Replace(name, "~1", "/", -1), "~0", "~", -1)
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("validate: the OpenAPI \"$ref\" '%s' is not found", ref)
+		}
+
+		if cur, ok = m[name]; !ok {
+			return nil, fmt.Errorf("validate: the OpenAPI \"$ref\" '%s' is not found", ref)
+		}
+	}
+	return cur, nil
+}
+
+// convert the OpenAPI 3.0 "nullable" keyword, the "null" is added to the types. eg:
+// 	{"type": "string", "nullable": true} -> {"type": ["string", "null"]}
+func convertOpenAPINullable(m map[string]interface{}) {
+	types, ok := jsonStrings(m["type"])
+	// no type, the null is allowed
+	if !ok || strSliceHas(types, "null") {
+		return
+	}
+
+	list := make([]interface{}, 0, len(types)+1)
+	for _, typ := range types {
+		list = append(list, typ)
+	}
+	m["type"] = append(list, "null")
+}
+
+// convert the OpenAPI 3.0 boolean exclusive keyword. eg:
+// 	{"minimum": 1, "exclusiveMinimum": true} -> {"exclusiveMinimum": 1}
+func convertOpenAPIExclusive(m map[string]interface{}, key, exKey string) {
+	ex, ok := m[exKey].(bool)
+	if !ok {
+		return
+	}
+
+	delete(m, exKey)
+	if bound, has := m[key]; has && ex {
+		delete(m, key)
+		m[exKey] = bound
+	}
+}
@@ -0,0 +1,306 @@
+package validate
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type openAPIUser struct {
+	Name  string   `json:"name" validate:"required|minLen:2" message:"required:the name is required"`
+	Age   int      `json:"age" validate:"int|between:1,120"`
+	Email string   `json:"email,omitempty" validate:"email"`
+	Tags  []string `json:"tags"`
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	is := assert.New(t)
+
+	schemas, err := OpenAPISchemas(&openAPIUser{})
+	is.NoError(err)
+	is.Contains(schemas, "openAPIUser")
+
+	doc := schemas["openAPIUser"].(M)
+	is.NotContains(doc, "$schema")
+	is.Equal(
+		`{"properties":{"age":{"maximum":120,"minimum":1,"type":"integer"},"email":{"format":"email","type":"string"},`+
+			`"name":{"minLength":2,"type":"string","x-validate-messages":{"required":"the name is required"}},`+
+			`"tags":{"items":{"type":"string"},"type":"array"}},"required":["name"],"type":"object"}`,
+		jsonString(doc),
+	)
+
+	// import with the messages
+	sr, err := ParseJSONSchema([]byte(jsonString(doc)))
+	is.NoError(err)
+	is.Empty(sr.Unsupported)
+	is.Equal(MS{"name.requiredKey": "the name is required"}, sr.Messages)
+
+	res := sr.Schema().Validate(FromMap(M{"age": 2}), "")
+	is.Equal("the name is required", res.Errors.One())
+
+	_, err = OpenAPISchemas(&openAPIUser{}, openAPIUser{})
+	is.Error(err)
+	_, err = OpenAPISchemas(struct{ Name string }{})
+	is.Error(err)
+	_, err = OpenAPISchemas("invalid")
+	is.Error(err)
+}
+
+var openAPISpec = `{
+  "openapi": "3.0.3",
+  "paths": {
+    "/users/{id}": {
+      "parameters": [
+        {"name": "id", "in": "path", "schema": {"type": "integer", "minimum": 1}}
+      ],
+      "put": {
+        "parameters": [
+          {"$ref": "#/components/parameters/Tags"},
+          {"name": "verbose", "in": "query", "schema": {"type": "boolean"}},
+          {"name": "X-Request-Id", "in": "header", "required": true, "schema": {"type": "string", "format": "uuid"}},
+          {"name": "limit", "in": "query", "schema": {"type": "integer", "maximum": 100, "exclusiveMaximum": true, "example": 10}}
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {"schema": {"$ref": "#/components/schemas/User"}},
+            "application/x-www-form-urlencoded": {"schema": {"$ref": "#/components/schemas/User"}}
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "Tags": {"name": "tags", "in": "query", "explode": false, "schema": {"type": "array", "items": {"type": "string", "enum": ["go", "php"]}}}
+    },
+    "schemas": {
+      "User": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+          "name": {"type": "string", "minLength": 2, "nullable": true},
+          "age": {"type": "integer", "minimum": 1},
+          "friend": {"$ref": "#/components/schemas/Friend"}
+        }
+      },
+      "Friend": {
+        "type": "object",
+        "properties": {"name": {"type": "string", "maxLength": 5}}
+      }
+    }
+  }
+}`
+
+func TestOpenAPIValidator(t *testing.T) {
+	is := assert.New(t)
+
+	ov, err := NewOpenAPIValidator([]byte(openAPISpec), "PUT", "/users/{id}")
+	is.NoError(err)
+	is.Empty(ov.Unsupported)
+
+	newReq := func(query, cType, body string) *http.Request {
+		r := httptest.NewRequest("PUT", "/users/12?"+query, strings.NewReader(body))
+		r.Header.Set("X-Request-Id", "5f2b5c9e-6a3d-4a1b-9c2e-1b2c3d4e5f6a")
+		if cType != "" {
+			r.Header.Set("Content-Type", cType)
+		}
+		return r
+	}
+
+	id := map[string]string{"id": "12"}
+	// ok
+	r := newReq("tags=go,php&verbose=true&limit=99", "application/json", `{"name": "inhere", "age": 20}`)
+	res := ov.Validate(r, id)
+	is.True(res.IsOK(), res.Errors)
+
+	form := url.Values{"name": {"inhere"}, "age": {"20"}}
+	r = newReq("", "application/x-www-form-urlencoded", form.Encode())
+	res = ov.Validate(r, id)
+	is.True(res.IsOK(), res.Errors)
+
+	tests := []struct {
+		query, cType, body string
+		pathParams         map[string]string
+		fields             []string
+	}{
+		// path
+		{"", "application/json", `{"name": "inhere"}`, map[string]string{"id": "0"}, []string{"path.id"}},
+		{"", "application/json", `{"name": "inhere"}`, map[string]string{"id": "abc"}, []string{"path.id"}},
+		{"", "application/json", `{"name": "inhere"}`, nil, []string{"path.id"}},
+		// query
+		{"tags=go,java", "application/json", `{"name": "inhere"}`, id, []string{"query.tags.1"}},
+		{"verbose=yes", "application/json", `{"name": "inhere"}`, id, []string{"query.verbose"}},
+		{"limit=100", "application/json", `{"name": "inhere"}`, id, []string{"query.limit"}},
+		// body
+		{"", "", "", id, []string{"body"}},
+		{"", "application/json", `{"age": 20}`, id, []string{"body.name"}},
+		// the zero value is validated
+		{"", "application/json", `{"name": "inhere", "age": 0}`, id, []string{"body.age"}},
+		{"", "application/json", `{"name": "inhere", "friend": {"name": "too long"}}`, id, []string{"body.friend.name"}},
+		{"", "application/x-www-form-urlencoded", "name=i&age=abc", id, []string{"body.age"}},
+		{"", "text/plain", "hello", id, []string{"_validate"}},
+		{"", "application/json", `{"name": `, id, []string{"_validate"}},
+	}
+
+	for _, tt := range tests {
+		r := newReq(tt.query, tt.cType, tt.body)
+		res := ov.Validate(r, tt.pathParams)
+		is.True(res.IsFail(), tt)
+		is.Equal(tt.fields, sortedFields(res.FieldErrors()), tt)
+	}
+
+	// header
+	r = newReq("", "application/json", `{"name": "inhere"}`)
+	r.Header.Set("X-Request-Id", "invalid")
+	res = ov.Validate(r, id)
+	is.Equal([]string{"header.X-Request-Id"}, res.FieldErrors().Fields())
+
+	r = newReq("", "application/json", `{"name": "inhere"}`)
+	r.Header.Del("X-Request-Id")
+	res = ov.Validate(r, id)
+	is.Equal([]string{"header.X-Request-Id"}, res.FieldErrors().Fields())
+}
+
+func TestOpenAPIValidator_nullable(t *testing.T) {
+	is := assert.New(t)
+
+	ov, err := NewOpenAPIValidator([]byte(`{"paths": {"/items": {"post": {
+		"parameters": [{"name": "page", "in": "query", "required": true, "schema": {"type": "integer", "minimum": 0}}],
+		"requestBody": {"required": true, "content": {"application/json": {"schema": {
+			"type": "object",
+			"required": ["name", "count"],
+			"properties": {
+				"name": {"type": "string", "minLength": 2, "nullable": true},
+				"count": {"type": "integer", "minimum": 0},
+				"note": {"nullable": true}
+			}
+		}}}}
+	}}}}`), "POST", "/items")
+	is.NoError(err)
+	is.Empty(ov.Unsupported)
+
+	tests := []struct {
+		query, body string
+		// the first error field, empty is ok
+		field string
+	}{
+		// the zero values are present
+		{"page=0", `{"name": "ab", "count": 0}`, ""},
+		{"page=0", `{"name": null, "count": 0, "note": null}`, ""},
+		{"page=0", `{"name": "ab"}`, "body.count"},
+		{"page=0", `{"name": "ab", "count": null}`, "body.count"},
+		{"page=0", `{"name": "a", "count": 0}`, "body.name"},
+		{"page=-1", `{"name": "ab", "count": 0}`, "query.page"},
+		{"", `{"name": "ab", "count": 0}`, "query.page"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/items?"+tt.query, strings.NewReader(tt.body))
+		r.Header.Set("Content-Type", "application/json")
+
+		res := ov.Validate(r, nil)
+		if tt.field == "" {
+			is.True(res.IsOK(), tt)
+		} else {
+			is.Equal(tt.field, res.FieldErrors()[0].Field, tt)
+		}
+	}
+}
+
+func TestOpenAPIValidator_keywordNames(t *testing.T) {
+	is := assert.New(t)
+
+	// the properties named like the OpenAPI keywords
+	ov, err := NewOpenAPIValidator([]byte(`{"paths": {"/items": {"post": {
+		"requestBody": {"content": {"application/json": {"schema": {
+			"type": "object",
+			"example": {"example": 1},
+			"properties": {
+				"example": {"type": "integer"},
+				"nullable": {"type": "boolean"},
+				"xml": {"type": "string", "maxLength": 3},
+				"addr": {
+					"type": "object",
+					"required": ["city"],
+					"properties": {"city": {"type": "string"}, "example": {"type": "integer", "example": 2}}
+				}
+			}
+		}}}}
+	}}}}`), "POST", "/items")
+	is.NoError(err)
+	is.Empty(ov.Unsupported)
+
+	tests := []struct {
+		body string
+		// the first error field, empty is ok
+		field string
+	}{
+		{`{"example": 1, "nullable": true, "xml": "abc"}`, ""},
+		{`{"example": "notint"}`, "body.example"},
+		{`{"nullable": "yes"}`, "body.nullable"},
+		{`{"xml": "abcd"}`, "body.xml"},
+		// the nested required is checked on the parent exists
+		{`{}`, ""},
+		{`{"addr": {"city": "a"}}`, ""},
+		{`{"addr": {}}`, "body.addr.city"},
+		{`{"addr": {"city": "a", "example": "b"}}`, "body.addr.example"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/items", strings.NewReader(tt.body))
+		r.Header.Set("Content-Type", "application/json")
+
+		res := ov.Validate(r, nil)
+		if tt.field == "" {
+			is.True(res.IsOK(), tt)
+		} else {
+			is.Equal(tt.field, res.FieldErrors()[0].Field, tt)
+		}
+	}
+}
+
+func TestNewOpenAPIValidator_error(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		spec, method, path string
+	}{
+		{`invalid`, "GET", "/"},
+		{`[]`, "GET", "/"},
+		{`{"paths": {}}`, "GET", "/users"},
+		{`{"paths": {"/users": {}}}`, "GET", "/users"},
+		{`{"paths": {"/users": {"get": {"parameters": [{"$ref": "#/components/parameters/none"}]}}}}`, "GET", "/users"},
+		{`{"paths": {"/users": {"get": {"parameters": [{"$ref": "other.json#/id"}]}}}}`, "GET", "/users"},
+		{`{"paths": {"/users": {"get": {"parameters": [{"name": "id", "in": "body"}]}}}}`, "GET", "/users"},
+		{`{"paths": {"/users": {"get": {"parameters": [{"$ref": "#/components/parameters/a"}]}}},
+		  "components": {"parameters": {"a": {"$ref": "#/components/parameters/a"}}}}`, "GET", "/users"},
+		{`{"paths": {"/users": {"post": {"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/a"}}}}}}},
+		  "components": {"schemas": {"a": {"$ref": "#/components/schemas/a"}}}}`, "POST", "/users"},
+	}
+
+	for _, tt := range tests {
+		_, err := NewOpenAPIValidator([]byte(tt.spec), tt.method, tt.path)
+		is.Error(err, tt.spec)
+	}
+
+	// unsupported keywords
+	ov, err := NewOpenAPIValidator([]byte(`{"paths": {"/users": {"post": {
+		"parameters": [{"name": "ids", "in": "query", "schema": {"type": "array", "uniqueItems": true}}],
+		"requestBody": {"content": {"application/json": {"schema": {"type": "object", "minProperties": 1, "oneOf": []}}}}
+	}}}}`), "POST", "/users")
+	is.NoError(err)
+	is.Equal([]string{
+		"/paths/~1users/post/requestBody/content/application~1json/schema/oneOf",
+		"/paths/~1users/post/parameters/0/schema/uniqueItems",
+	}, ov.Unsupported)
+
+	// no body is required
+	r := httptest.NewRequest("POST", "/users", bytes.NewReader(nil))
+	is.True(ov.Validate(r, nil).IsOK())
+}
@@ -1,36 +1,249 @@
 package validate
 
-import "reflect"
+import (
+	"net/url"
+	"reflect"
+	"sync"
+)
+
+// the default global registry. the package level functions use it.
+// eg: AddValidator(), AddFilter(), AddGlobalMessages()
+var defRegistry = NewRegistry()
 
-var (
-	// global validators. contains built-in and user custom
+// Registry holds the validators, filters, alias names and messages.
+// It is goroutine-safe, can register while other goroutines are validating.
+//
+// The package level functions use the default registry, you can create an isolated
+// registry for avoid the name conflicts with other libraries.
+//
+// Usage:
+// 	reg := validate.NewRegistry()
+// 	reg.AddValidator("phone", func(val string) bool { ... })
+// 	v := reg.Struct(&form)
+type Registry struct {
+	mu sync.RWMutex
+	// validators. contains built-in and user custom. 1: built in 2: custom
 	validators map[string]int8
 	// all validators func meta information
 	validatorMetas map[string]*funcMeta
-)
+	// validator alias name mapping. alias -> real name
+	aliases map[string]string
+	// filter func reflect.Value map
+	filterValues map[string]reflect.Value
+	// error messages
+	messages map[string]string
+	// caches parsed struct info. it is cleared on the validators or alias names are changed.
+	structs *factory
+}
 
-// init: register all built-in validators
-func init() {
-	validators = make(map[string]int8)
-	validatorMetas = make(map[string]*funcMeta)
+// NewRegistry create a registry with the built-in validators, alias names and messages.
+func NewRegistry() *Registry {
+	r := &Registry{
+		validators:     make(map[string]int8, len(validatorValues)),
+		validatorMetas: make(map[string]*funcMeta, len(validatorValues)),
+		aliases:        make(map[string]string, len(validatorAliases)),
+		filterValues:   make(map[string]reflect.Value),
+		messages:       make(map[string]string, len(builtinMessages)),
+	}
 
 	for n, fv := range validatorValues {
-		validators[n] = 1 // built in
-		validatorMetas[n] = newFuncMeta(n, true, fv)
+		r.validators[n] = 1 // built in
+		r.validatorMetas[n] = newFuncMeta(n, true, fv)
+	}
+
+	for alias, name := range validatorAliases {
+		r.aliases[alias] = name
+	}
+
+	for key, msg := range builtinMessages {
+		r.messages[key] = msg
+	}
+
+	r.structs = newFactory(r)
+	return r
+}
+
+// DefaultRegistry get the default registry, it is used by the package level functions.
+func DefaultRegistry() *Registry {
+	return defRegistry
+}
+
+// AddValidators to the registry
+func (r *Registry) AddValidators(m map[string]interface{}) {
+	for name, checkFunc := range m {
+		r.AddValidator(name, checkFunc)
+	}
+}
+
+// AddValidator to the registry. checkFunc must return a bool.
+func (r *Registry) AddValidator(name string, checkFunc interface{}) {
+	fv := checkValidatorFunc(name, checkFunc)
+	fm := newFuncMeta(name, false, fv)
+
+	r.mu.Lock()
+	r.validators[name] = 2 // custom
+	r.validatorMetas[name] = fm
+	r.mu.Unlock()
+
+	// the cached rules maybe use it as alias name
+	r.structs.reset()
+}
+
+func (r *Registry) validatorMeta(name string) (fm *funcMeta, ok bool) {
+	r.mu.RLock()
+	fm, ok = r.validatorMetas[name]
+	r.mu.RUnlock()
+	return
+}
+
+// HasValidator check by name, the name can be an alias name.
+func (r *Registry) HasValidator(name string) bool {
+	_, ok := r.validatorMeta(r.ValidatorName(name))
+	return ok
+}
+
+// Validators get all validator names. returns a copy of the names map.
+func (r *Registry) Validators() map[string]int8 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	mp := make(map[string]int8, len(r.validators))
+	for name, typ := range r.validators {
+		mp[name] = typ
+	}
+	return mp
+}
+
+// AddAlias add an alias name for the validator.
+func (r *Registry) AddAlias(alias, name string) {
+	r.mu.Lock()
+	r.aliases[alias] = name
+	r.mu.Unlock()
+
+	r.structs.reset()
+}
+
+// ValidatorName get real validator name by the alias name.
+func (r *Registry) ValidatorName(name string) string {
+	if rName, ok := r.realName(name); ok {
+		return rName
 	}
+	return name
+}
+
+// get real validator name by alias name
+func (r *Registry) realName(alias string) (name string, ok bool) {
+	r.mu.RLock()
+	name, ok = r.aliases[alias]
+	r.mu.RUnlock()
+	return
+}
+
+// AddFilters to the registry
+func (r *Registry) AddFilters(m map[string]interface{}) {
+	for name, filterFunc := range m {
+		r.AddFilter(name, filterFunc)
+	}
+}
+
+// AddFilter to the registry
+func (r *Registry) AddFilter(name string, filterFunc interface{}) {
+	fv := checkFilterFunc(name, filterFunc)
+
+	r.mu.Lock()
+	r.filterValues[name] = fv
+	r.mu.Unlock()
+}
+
+func (r *Registry) filterValue(name string) (fv reflect.Value, ok bool) {
+	r.mu.RLock()
+	fv, ok = r.filterValues[name]
+	r.mu.RUnlock()
+	return
+}
+
+// AddMessages to the registry. the Validation created after will use them.
+func (r *Registry) AddMessages(mp map[string]string) {
+	r.mu.Lock()
+	for key, msg := range mp {
+		r.messages[key] = msg
+	}
+	r.mu.Unlock()
+}
+
+// Messages get all messages. returns a copy of the messages map.
+func (r *Registry) Messages() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	mp := make(map[string]string, len(r.messages))
+	for key, msg := range r.messages {
+		mp[key] = msg
+	}
+	return mp
+}
+
+/*************************************************************
+ * create Validation by the registry
+ *************************************************************/
+
+// New create a Validation instance use the registry. see New()
+func (r *Registry) New(data interface{}, scene ...string) *Validation {
+	switch td := data.(type) {
+	case DataFace:
+		return r.NewValidation(td, scene...)
+	case M:
+		return r.NewValidation(FromMap(td), scene...)
+	case map[string]interface{}:
+		return r.NewValidation(FromMap(td), scene...)
+	case SValues:
+		return r.NewValidation(FromURLValues(url.Values(td)), scene...)
+	case url.Values:
+		return r.NewValidation(FromURLValues(td), scene...)
+	case map[string][]string:
+		return r.NewValidation(FromURLValues(td), scene...)
+	}
+
+	return r.Struct(data, scene...)
+}
+
+// NewEmpty create a Validation instance use the registry, but not add data.
+func (r *Registry) NewEmpty(scene ...string) *Validation {
+	return r.NewValidation(nil, scene...)
+}
+
+// NewValidation create a Validation instance use the registry.
+func (r *Registry) NewValidation(data DataFace, scene ...string) *Validation {
+	return newValidationWith(r, data).SetScene(scene...)
+}
+
+// Map create a Validation instance use the registry.
+func (r *Registry) Map(m map[string]interface{}, scene ...string) *Validation {
+	return r.NewValidation(FromMap(m), scene...)
+}
+
+// Struct create a Validation instance use the registry.
+// will collect rules from the struct tags.
+func (r *Registry) Struct(s interface{}, scene ...string) *Validation {
+	d, err := FromStruct(s)
+	if err != nil {
+		return r.NewValidation(nil).WithError(err).SetScene(scene...)
+	}
+
+	return d.create(r).SetScene(scene...)
 }
 
-// validator func reflect.Value
+// built-in validator func reflect.Value
 var validatorValues = map[string]reflect.Value{
 	// int value
-	"lt":  reflect.ValueOf(Lt),
-	"gt":  reflect.ValueOf(Gt),
-	"min": reflect.ValueOf(Min),
-	"max": reflect.ValueOf(Max),
+	"lt":  reflect.ValueOf(LtNumber),
+	"gt":  reflect.ValueOf(GtNumber),
+	"min": reflect.ValueOf(MinNumber),
+	"max": reflect.ValueOf(MaxNumber),
 	// value check
 	"enum":     reflect.ValueOf(Enum),
 	"notIn":    reflect.ValueOf(NotIn),
-	"between":  reflect.ValueOf(Between),
+	"between":  reflect.ValueOf(BetweenNumber),
 	"regexp":   reflect.ValueOf(Regexp),
 	"isEqual":  reflect.ValueOf(IsEqual),
 	"intEqual": reflect.ValueOf(IntEqual),
@@ -48,6 +261,7 @@ var validatorValues = map[string]reflect.Value{
 	"isUint":    reflect.ValueOf(IsUint),
 	"isBool":    reflect.ValueOf(IsBool),
 	"isFloat":   reflect.ValueOf(IsFloat),
+	"isDecimal": reflect.ValueOf(IsDecimal),
 	"isInts":    reflect.ValueOf(IsInts),
 	"isArray":   reflect.ValueOf(IsArray),
 	"isSlice":   reflect.ValueOf(IsSlice),
@@ -60,6 +274,8 @@ var validatorValues = map[string]reflect.Value{
 	"stringLength": reflect.ValueOf(StringLength),
 	// string
 	"isIntString": reflect.ValueOf(IsIntString),
+	// JSON Schema type
+	"isJSONType": reflect.ValueOf(IsJSONType),
 	// ip
 	"isIP":        reflect.ValueOf(IsIP),
 	"isIPv4":      reflect.ValueOf(IsIPv4),
@@ -113,9 +329,13 @@ var validatorValues = map[string]reflect.Value{
 	// ---
 	"afterOrEqualDate":  reflect.ValueOf(AfterOrEqualDate),
 	"beforeOrEqualDate": reflect.ValueOf(BeforeOrEqualDate),
+	// time, duration
+	"isWeekday":   reflect.ValueOf(IsWeekday),
+	"minDuration": reflect.ValueOf(MinDuration),
+	"maxDuration": reflect.ValueOf(MaxDuration),
 }
 
-// define validator alias name mapping
+// define built-in validator alias name mapping
 var validatorAliases = map[string]string{
 	// alias -> real name
 	"in":     "enum",
@@ -127,6 +347,8 @@ var validatorAliases = map[string]string{
 	"uint":      "isUint",
 	"bool":      "isBool",
 	"float":     "isFloat",
+	"decimal":   "isDecimal",
+	"jsonType":  "isJSONType",
 	"map":       "isMap",
 	"ints":      "isInts", // []int
 	"int_slice": "isInts",
@@ -149,6 +371,8 @@ var validatorAliases = map[string]string{
 	// int compare
 	"lte":          "max",
 	"gte":          "min",
+	"minFloat":     "min",
+	"maxFloat":     "max",
 	"lessThan":     "lt",
 	"less_than":    "lt",
 	"greaterThan":  "gt",
@@ -320,6 +544,11 @@ var validatorAliases = map[string]string{
 	"gte_date": "afterOrEqualDate",
 	"lteDate":  "beforeOrEqualDate",
 	"lte_date": "beforeOrEqualDate",
+	// time, duration
+	"weekday":      "isWeekday",
+	"within_last":  "withinLast",
+	"min_duration": "minDuration",
+	"max_duration": "maxDuration",
 	// uploaded file
 	"img":          "isImage",
 	"image":        "isImage",
@@ -340,6 +569,7 @@ var validatorAliases = map[string]string{
 	"lt_field":  "ltField",
 	"lte_field": "lteField",
 	// requiredXXX
+	"required_key":         "requiredKey",
 	"required_if":          "requiredIf",
 	"required_unless":      "requiredUnless",
 	"required_with":        "requiredWith",
@@ -0,0 +1,175 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_concurrent(t *testing.T) {
+	is := assert.New(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		// register
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("regCheck%d", i)
+			AddValidator(name, func(val interface{}) bool { return true })
+			AddFilter(fmt.Sprintf("regFilter%d", i), func(val interface{}) interface{} { return val })
+			AddGlobalMessages(map[string]string{name: "{field} is invalid"})
+			Config(func(opt *GlobalOption) {
+				opt.CheckZero = false
+			})
+		}(i)
+
+		// validate
+		go func() {
+			defer wg.Done()
+			v := Map(M{"name": "inhere", "age": 20})
+			v.StringRules(MS{"name": "required|minLen:3", "age": "int"})
+			v.FilterRule("name", "trim")
+			is.True(v.Validate())
+			is.True(v.HasValidator("min"))
+			_ = Validators()
+			_ = BuiltinMessages()
+			_ = Option()
+		}()
+	}
+	wg.Wait()
+
+	is.Contains(Validators(), "regCheck7")
+	is.Contains(BuiltinMessages(), "regCheck7")
+	is.True(NewEmpty().FilterFuncValue("regFilter7").IsValid())
+
+	// returns a copy
+	Validators()["regCheck7"] = 1
+	is.Equal(int8(2), Validators()["regCheck7"])
+}
+
+func TestRegistry_isolated(t *testing.T) {
+	is := assert.New(t)
+
+	cnReg := NewRegistry()
+	cnReg.AddValidator("phone", func(val string) bool {
+		return IsCnMobile(val)
+	})
+	cnReg.AddMessages(MS{"phone": "{field} must be a cn mobile"})
+
+	usReg := NewRegistry()
+	usReg.AddValidator("phone", func(val string) bool {
+		return len(val) == 10
+	})
+	usReg.AddAlias("usPhone", "phone")
+	usReg.AddFilter("digits", func(val string) string {
+		return strings.Replace(val, "-", "", -1)
+	})
+
+	is.True(cnReg.HasValidator("phone"))
+	is.True(usReg.HasValidator("usPhone"))
+	is.False(DefaultRegistry().HasValidator("phone"))
+	is.False(NewEmpty().HasValidator("phone"))
+	is.Equal("phone", usReg.ValidatorName("usPhone"))
+	is.Equal("isInt", usReg.ValidatorName("int"))
+
+	mp := M{"tel": "13800138000"}
+	v := cnReg.Map(mp)
+	v.StringRule("tel", "required|phone")
+	is.True(v.Validate())
+
+	v = usReg.New(mp)
+	v.StringRule("tel", "required|usPhone")
+	is.False(v.Validate())
+	is.Equal("tel field did not pass validation", v.Errors.One())
+
+	v = cnReg.New(M{"tel": "123"})
+	v.StringRule("tel", "phone")
+	is.False(v.Validate())
+	is.Equal("tel must be a cn mobile", v.Errors.One())
+
+	// filter
+	v = usReg.Map(M{"tel": "555-123-4567"})
+	v.FilterRule("tel", "digits")
+	v.StringRule("tel", "phone")
+	is.True(v.Validate())
+	is.Equal("5551234567", v.SafeVal("tel"))
+
+	// struct
+	type user struct {
+		Tel string `validate:"required|phone"`
+	}
+	v = cnReg.Struct(&user{Tel: "13800138000"})
+	is.True(v.Validate())
+	v = usReg.Struct(&user{Tel: "13800138000"})
+	is.False(v.Validate())
+	v = usReg.Struct(nil)
+	is.Equal(ErrInvalidData.Error(), v.Errors.One())
+
+	// schema keeps the registry
+	s := usReg.NewEmpty().StringRules(MS{"tel": "phone"}).Compile()
+	is.True(s.Validate(FromMap(M{"tel": "5551234567"}), "").IsOK())
+
+	// custom validator has same name as the built-in validator
+	reg := NewRegistry()
+	reg.AddValidator("min", func(val interface{}, min int64) bool {
+		return false
+	})
+	v = reg.Map(M{"age": 20})
+	v.AddRule("age", "min", 1)
+	is.False(v.Validate())
+	v = Map(M{"age": 20})
+	v.AddRule("age", "min", 1)
+	is.True(v.Validate())
+}
+
+func TestRegistry_structTagAlias(t *testing.T) {
+	is := assert.New(t)
+
+	reg := NewRegistry()
+	reg.AddAlias("oneOf", "enum")
+	reg.AddAlias("match", "regexp")
+
+	type form struct {
+		Kind string `validate:"oneOf:a,b" message:"oneOf:kind is invalid"`
+		Code string `validate:"match:^(x|y)$"`
+	}
+
+	// the default registry has not the aliases, the cached rules should not be shared.
+	v := Struct(&form{Kind: "a", Code: "x"})
+	is.False(v.Validate())
+
+	v = reg.Struct(&form{Kind: "a", Code: "y"})
+	is.True(v.Validate())
+
+	v = reg.Struct(&form{Kind: "c", Code: "x"})
+	is.False(v.Validate())
+	is.Equal("kind is invalid", v.Errors.One())
+
+	v = reg.Struct(&form{Kind: "b", Code: "xy"})
+	is.False(v.Validate())
+	is.Equal("Code", v.FieldErrors()[0].Field)
+}
+
+func TestRegistry_addAliasAfterStruct(t *testing.T) {
+	is := assert.New(t)
+
+	type form struct {
+		Code string `validate:"match:^[a-z]{2,3}$" message:"match:code is invalid|required:code is required"`
+	}
+
+	// the rules of the form are parsed and cached before the alias is added
+	reg := NewRegistry()
+	reg.Struct(&form{Code: "ab"})
+
+	reg.AddAlias("match", "regexp")
+	v := reg.Struct(&form{Code: "ab"})
+	is.True(v.Validate())
+
+	v = reg.Struct(&form{Code: "a"})
+	is.False(v.Validate())
+	is.Equal("code is invalid", v.Errors.One())
+}
@@ -1,6 +1,7 @@
 package validate
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -21,6 +22,8 @@ type Rule struct {
 	optional bool
 	// skip validate not exist field/empty value
 	skipEmpty bool
+	// skip validate not exist field, but the empty value will be validated. like the JSON Schema
+	skipMissing bool
 	// default value setting
 	defValue interface{}
 	// error message
@@ -42,12 +45,18 @@ type Rule struct {
 	// --- some hooks function
 	// has beforeFunc. if return false, skip validate current rule
 	beforeFunc func(v *Validation) bool // func (val interface{}) bool
+	// the condition checked for each real path of the fields, if return false, skip validate the path.
+	// eg: the "when:Type=company" on the "Items.*.TaxID" checks the "Items.0.Type" for the "Items.0.TaxID"
+	fieldCond func(v *Validation, field string) bool
 	// you can custom filter func
 	filterFunc func(val interface{}) (interface{}, error)
 	// custom check func's mate info
 	checkFuncMeta *funcMeta
 	// custom check is empty.
 	emptyChecker func(val interface{}) bool
+	// the alternative rules of the group. eg: "(isEmail or isCnMobile)"
+	// the group is passed if any of the alternatives is passed.
+	anyOf []*Rule
 }
 
 // NewRule create new Rule instance
@@ -130,17 +139,62 @@ func (r *Rule) Fields() []string {
 	return r.fields
 }
 
-func (r *Rule) errorMessage(field, validator string, v *Validation) (msg string) {
+// String get the rule string of the validator. eg: "min:12", "(isEmail or isCnMobile)"
+func (r *Rule) String() string {
+	if r.anyOf != nil {
+		ss := make([]string, len(r.anyOf))
+		for i, alt := range r.anyOf {
+			ss[i] = alt.String()
+		}
+		return "(" + strings.Join(ss, " or ") + ")"
+	}
+
+	if len(r.arguments) == 0 {
+		return r.validator
+	}
+
+	ss := make([]string, len(r.arguments))
+	for i, arg := range r.arguments {
+		ss[i] = fmt.Sprint(arg)
+	}
+	return r.validator + ":" + strings.Join(ss, ",")
+}
+
+// clone a new rule, the fields, args and messages are copied.
+func (r *Rule) clone() *Rule {
+	nr := *r
+	nr.fields = append([]string(nil), r.fields...)
+	nr.arguments = append([]interface{}(nil), r.arguments...)
+
 	if r.messages != nil {
-		var ok bool
-		// use full key. "field.validator"
-		fKey := field + "." + validator
-		if msg, ok = r.messages[fKey]; ok {
-			return
+		nr.messages = make(map[string]string, len(r.messages))
+		for k, msg := range r.messages {
+			nr.messages[k] = msg
 		}
+	}
 
-		if msg, ok = r.messages[field]; ok {
-			return
+	if r.anyOf != nil {
+		nr.anyOf = make([]*Rule, len(r.anyOf))
+		for i, alt := range r.anyOf {
+			nr.anyOf[i] = alt.clone()
+		}
+	}
+	return &nr
+}
+
+// build error message for the field. pattern is the field name in the rule, maybe contains wildcard.
+func (r *Rule) errorMessage(field, pattern, validator string, v *Validation, args ...interface{}) (msg string) {
+	if r.messages != nil {
+		var ok bool
+		for _, name := range fieldAndPattern(field, pattern) {
+			// use full key. "field.validator"
+			if msg, ok = r.messages[name+"."+validator]; ok {
+				return
+			}
+
+			if msg, ok = r.messages[name]; ok {
+				return
+			}
 		}
 	}
 
@@ -149,7 +203,7 @@ func (r *Rule) errorMessage(field, validator string, v *Validation) (msg string)
 	}
 
 	// built in error messages
-	return v.trans.Message(validator, field, r.arguments...)
+	return v.fieldMessage(validator, field, pattern, args...)
 }
 
 /*************************************************************
@@ -161,48 +215,110 @@ func (r *Rule) errorMessage(field, validator string, v *Validation) (msg string)
 // 	v.StringRule("name", "required|string|minLen:6")
 // 	// will try convert to int before apply validate.
 // 	v.StringRule("age", "required|int|min:12", "toInt")
+//
+// the argument can be quoted, see the syntax in rule_parser.go
+// 	v.StringRule("code", `regexp:"^(a|b)$"`)
+// 	v.StringRule("tag", `in:'x,y','z'`)
+//
+// on the rule is invalid, will add a RuleParseError to the errors.
 func (v *Validation) StringRule(field, rule string, filterRule ...string) *Validation {
-	rule = strings.TrimSpace(rule)
-	rules := stringSplit(strings.Trim(rule, "|:"), "|")
-	for _, validator := range rules {
-		validator = strings.Trim(validator, ":")
-		if validator == "" { // empty
+	items, err := parseRegistryRule(v.reg, rule)
+	if err != nil {
+		v.addRuleParseError(field, err)
+	} else {
+		v.addRuleItems(field, "", items)
+	}
+
+	if len(filterRule) > 0 {
+		v.FilterRule(field, filterRule[0])
+	}
+	return v
+}
+
+// ruleItem is one parsed validator of a string rule. eg: "min:12"
+type ruleItem struct {
+	// the input validator name
+	validator string
+	// raw arguments for the validator
+	args []interface{}
+	// the alternative items of a group. eg: "(isEmail or isCnMobile)"
+	anyOf []*ruleItem
+	// the condition of the "when" item. eg: "when:type=company"
+	cond *condition
+}
+
+// add the rule parse error for the field, the validate will be failed.
+func (v *Validation) addRuleParseError(field string, err *RuleParseError) {
+	// NOTICE: copy the error, it maybe shared by cache.
+	pe := *err
+	pe.Field = field
+	v.AddFieldError(&FieldError{
+		Field:     field,
+		Validator: validateError,
+		Message:   pe.Error(),
+		Err:       &pe,
+	})
+}
+
+// add parsed rule items for the field.
+// the structPath is the path of the struct contains the field, the conditions are relative to it. empty is the root.
+func (v *Validation) addRuleItems(field, structPath string, items []*ruleItem) {
+	for i, item := range items {
+		// the rules after the "when" item are conditional, until the next "when" item.
+		// eg: "when:type=person|required|when:type=company|minLen:6"
+		if item.cond != nil {
+			end := i + 1
+			for end < len(items) && items[end].cond == nil {
+				end++
+			}
+
+			block := func() {
+				v.addRuleItems(field, structPath, items[i+1:end])
+			}
+
+			if structPath == "" {
+				v.addWhenRules(item.cond.checkRoot, block)
+			} else {
+				v.addFieldWhenRules(item.cond.inStruct(structPath), block)
+			}
+			v.addRuleItems(field, structPath, items[end:])
+			return
+		}
+
+		// add a rule group
+		if len(item.anyOf) > 0 {
+			v.addAnyOfRule(field, item.anyOf)
 			continue
 		}
 
-		// has args "min:12"
-		if strings.ContainsRune(validator, ':') {
-			list := stringSplit(validator, ":")
-			// reassign value
-			validator := list[0]
-			realName := ValidatorName(validator)
-			switch realName {
-			// set error message for the field TODO
-			// case "message":
-			// 	// message key like "age.required"
-			// 	v.trans.AddMessage(field+"."+validator, list[1])
-			// add default value for the field
-			case "default":
-				v.SetDefValue(field, list[1])
-			// eg 'regex:\d{4,6}' dont need split args. args is "\d{4,6}"
-			case "regexp":
-				v.AddRule(field, validator, list[1])
-			// some special validator. need merge args to one.
-			case "enum", "notIn":
-				v.AddRule(field, validator, parseArgString(list[1]))
-			default:
-				args := parseArgString(list[1])
-				v.AddRule(field, validator, strings2Args(args)...)
-			}
-		} else {
-			v.AddRule(field, validator)
+		// add default value for the field
+		if len(item.args) > 0 && v.reg.ValidatorName(item.validator) == "default" {
+			v.SetDefValue(field, item.args[0])
+			continue
 		}
+
+		// NOTICE: copy args, the items maybe shared by cache.
+		args := make([]interface{}, len(item.args))
+		copy(args, item.args)
+		v.AddRule(field, item.validator, args...)
 	}
+}
 
-	if len(filterRule) > 0 {
-		v.FilterRule(field, filterRule[0])
+// add a rule group for the field, it is passed if any of the alternatives is passed.
+func (v *Validation) addAnyOfRule(fields string, items []*ruleItem) *Rule {
+	rule := v.addOneRule(fields, anyOfName, anyOfName, nil)
+	for _, item := range items {
+		realName := v.reg.ValidatorName(item.validator)
+		if realName == "default" || realName == whenName || isFileValidator(realName) {
+			panicf("the validator '%s' cannot be used in the rule group", item.validator)
+		}
+
+		alt := NewRule(fields, item.validator, append([]interface{}(nil), item.args...)...)
+		alt.realName = realName
+		alt.nameNotRequired = !strings.HasPrefix(realName, "required")
+		rule.anyOf = append(rule.anyOf, alt)
 	}
-	return v
+	return rule
 }
 
 // StringRules add multi rules by string map.
@@ -212,8 +328,9 @@ func (v *Validation) StringRule(field, rule string, filterRule ...string) *Valid
 // 		"age": "required|int|min:12",
 // 	})
 func (v *Validation) StringRules(mp MS) *Validation {
-	for name, rule := range mp {
-		v.StringRule(name, rule)
+	// add by sorted field names, keep the rules order is deterministic.
+	for _, name := range mp.sortedKeys() {
+		v.StringRule(name, mp[name])
 	}
 	return v
 }
@@ -225,15 +342,16 @@ func (v *Validation) StringRules(mp MS) *Validation {
 // 		"age": "required|int|min:12",
 // 	})
 func (v *Validation) ConfigRules(mp MS) *Validation {
-	for name, rule := range mp {
-		v.StringRule(name, rule)
+	// add by sorted field names, keep the rules order is deterministic.
+	for _, name := range mp.sortedKeys() {
+		v.StringRule(name, mp[name])
 	}
 	return v
 }
 
 // AddRule for current validation
 func (v *Validation) AddRule(fields, validator string, args ...interface{}) *Rule {
-	return v.addOneRule(fields, validator, ValidatorName(validator), args)
+	return v.addOneRule(fields, validator, v.reg.ValidatorName(validator), args)
 }
 
 // add one Rule for current validation
@@ -253,7 +371,7 @@ func (v *Validation) addOneRule(fields, validator, realName string, args []inter
 
 // AppendRule instance
 func (v *Validation) AppendRule(rule *Rule) *Rule {
-	rule.realName = ValidatorName(rule.validator)
+	rule.realName = v.reg.ValidatorName(rule.validator)
 	rule.skipEmpty = v.SkipOnEmpty
 	// validator name is not "required"
 	rule.nameNotRequired = !strings.HasPrefix(rule.realName, "required")
@@ -0,0 +1,370 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+/*************************************************************
+ * string rule parser. the syntax:
+ *  - validators are separated by "|". eg: "required|minLen:6"
+ *  - arguments are after the ":", separated by ",". eg: "between:1,10"
+ *  - quote the argument by '' or "". eg: `in:'x,y','z'` `regexp:"^(a|b)$"`
+ *  - in the quoted argument, use "\" to escape the quote char and "\" self.
+ *  - in the unquoted argument, use "\" to escape the ",", "|", "(" and ")".
+ *  - group the alternative validators by "( or )". eg: "(isEmail or isCnMobile)"
+ *  - the validators after "when:cond" are applied on the cond is true. eg: "when:type=company|required"
+ *************************************************************/
+
+// RuleParseError is the error of parse a string rule.
+type RuleParseError struct {
+	// Field name of the rule. maybe is empty
+	Field string
+	// Rule the string rule
+	Rule string
+	// Column of the error position. start from 1
+	Column int
+	// Message of the error
+	Message string
+}
+
+// Error string
+func (e *RuleParseError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("invalid rule %q of the field '%s' at column %d: %s", e.Rule, e.Field, e.Column, e.Message)
+	}
+	return fmt.Sprintf("invalid rule %q at column %d: %s", e.Rule, e.Column, e.Message)
+}
+
+// ruleParser is a tokenizer and parser for the string rule
+type ruleParser struct {
+	src string
+	pos int
+	// for resolve the alias names of the special validators. eg: "default", "regexp"
+	reg *Registry
+}
+
+// parseStringRule parse a string rule to validator items, the alias names are resolved by the default registry.
+// eg: "required|string|minLen:6", "required|(isEmail or isCnMobile)"
+func parseStringRule(rule string) ([]*ruleItem, *RuleParseError) {
+	return parseRegistryRule(defRegistry, rule)
+}
+
+// parseRegistryRule parse a string rule, the alias names are resolved by the registry.
+func parseRegistryRule(reg *Registry, rule string) ([]*ruleItem, *RuleParseError) {
+	p := &ruleParser{src: rule, reg: reg}
+	return p.parse()
+}
+
+func (p *ruleParser) parse() (items []*ruleItem, err *RuleParseError) {
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			return
+		}
+
+		var item *ruleItem
+		switch c := p.peek(); c {
+		case '|': // empty validator
+			p.pos++
+			continue
+		case '(':
+			item, err = p.parseGroup()
+		case ')':
+			return nil, p.errorf(p.pos, "unexpected ')'")
+		default:
+			item, err = p.parseValidator(false)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		p.skipSpaces()
+		if !p.eof() && p.peek() != '|' {
+			return nil, p.errorf(p.pos, "unexpected %q, expect '|'", p.peek())
+		}
+	}
+}
+
+// parseGroup parse a rule group. eg: "(isEmail or isCnMobile)"
+func (p *ruleParser) parseGroup() (*ruleItem, *RuleParseError) {
+	start := p.pos
+	p.pos++ // skip '('
+
+	var group []*ruleItem
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			return nil, p.errorf(start, "the group is not closed")
+		}
+
+		c := p.peek()
+		if c == ')' {
+			if len(group) == 0 {
+				return nil, p.errorf(start, "the group is empty")
+			}
+			p.pos++
+			break
+		}
+
+		if len(group) > 0 {
+			if !p.hasOrAt(p.pos) {
+				return nil, p.errorf(p.pos, "unexpected %q, expect 'or' or ')'", c)
+			}
+			p.pos += 2
+			p.skipSpaces()
+			c = p.peek()
+		}
+
+		if c == '(' {
+			return nil, p.errorf(p.pos, "the nested group is not supported")
+		}
+
+		item, err := p.parseValidator(true)
+		if err != nil {
+			return nil, err
+		}
+		group = append(group, item)
+	}
+
+	if len(group) == 1 {
+		return group[0], nil
+	}
+	return &ruleItem{validator: anyOfName, anyOf: group}, nil
+}
+
+// parseValidator parse one validator and its arguments. eg: "required", "min:12"
+func (p *ruleParser) parseValidator(inGroup bool) (*ruleItem, *RuleParseError) {
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(":|()'\" \t\r\n", rune(p.peek())) {
+		p.pos++
+	}
+
+	if p.pos == start {
+		return nil, p.errorf(start, "missing the validator name")
+	}
+
+	item := &ruleItem{validator: p.src[start:p.pos]}
+	if p.eof() || p.peek() != ':' {
+		if item.validator == whenName {
+			return nil, p.errorf(start, "missing the condition for the 'when'")
+		}
+		return item, nil
+	}
+	p.pos++ // skip ':'
+
+	switch p.reg.ValidatorName(item.validator) {
+	// conditional rules. eg: "when:type=company"
+	case whenName:
+		argPos := p.pos
+		arg, _, err := p.parseRawArg(inGroup)
+		if err != nil {
+			return nil, err
+		}
+
+		cond, cErr := newCondition(arg)
+		if cErr != nil {
+			return nil, p.errorf(argPos, cErr.Error())
+		}
+		item.args = []interface{}{arg}
+		item.cond = cond
+	// eg 'regex:\d{4,6}' dont need split args. args is "\d{4,6}"
+	// "default" value for the field, no need split too.
+	case "regexp", "default":
+		arg, ok, err := p.parseRawArg(inGroup)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			item.args = []interface{}{arg}
+		}
+	default:
+		args, err := p.parseArgs(inGroup)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(args) > 0 {
+			// some special validator. need merge args to one.
+			if name := p.reg.ValidatorName(item.validator); name == "enum" || name == "notIn" {
+				item.args = []interface{}{args}
+			} else {
+				item.args = strings2Args(args)
+			}
+		}
+	}
+	return item, nil
+}
+
+// parseRawArg parse the whole arguments as one. the "\" is kept as is. eg: `regexp:^\d{4,6}$`
+// the "|" in the parentheses or brackets will not end the argument. eg: `regexp:^(a|b)$`
+func (p *ruleParser) parseRawArg(inGroup bool) (string, bool, *RuleParseError) {
+	p.skipSpaces()
+	if p.eof() {
+		return "", false, nil
+	}
+
+	if isQuote(p.peek()) {
+		arg, err := p.parseQuoted()
+		return arg, err == nil, err
+	}
+
+	var depth int
+	var inBracket bool
+	start := p.pos
+	for ; !p.eof(); p.pos++ {
+		c := p.peek()
+		if c == '\\' {
+			p.pos++
+			continue
+		}
+
+		if inBracket {
+			inBracket = c != ']'
+			continue
+		}
+
+		if depth == 0 && (c == '|' || inGroup && (c == ')' || p.hasOrAt(p.pos))) {
+			break
+		}
+
+		switch c {
+		case '[':
+			inBracket = true
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+
+	// NOTICE: the escaped char at end maybe out of range.
+	if p.pos > len(p.src) {
+		p.pos = len(p.src)
+	}
+
+	arg := strings.TrimSpace(p.src[start:p.pos])
+	return arg, arg != "", nil
+}
+
+// parseArgs parse the arguments separated by ",". eg: "1,10", `'x,y','z'`
+func (p *ruleParser) parseArgs(inGroup bool) (args []string, err *RuleParseError) {
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			return
+		}
+
+		if isQuote(p.peek()) {
+			arg, err := p.parseQuoted()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+		} else if arg := p.parseBareArg(inGroup); arg != "" {
+			args = append(args, arg)
+		}
+
+		p.skipSpaces()
+		if p.eof() || p.peek() != ',' {
+			return
+		}
+		p.pos++ // skip ','
+	}
+}
+
+// parseBareArg parse an unquoted argument.
+func (p *ruleParser) parseBareArg(inGroup bool) string {
+	var sb strings.Builder
+	for ; !p.eof(); p.pos++ {
+		c := p.peek()
+		if c == '\\' && p.pos+1 < len(p.src) && strings.IndexByte(`,|()\`, p.src[p.pos+1]) >= 0 {
+			p.pos++
+			sb.WriteByte(p.src[p.pos])
+			continue
+		}
+
+		if c == ',' || c == '|' || inGroup && (c == ')' || p.hasOrAt(p.pos)) {
+			break
+		}
+		sb.WriteByte(c)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// parseQuoted parse a quoted argument. eg: `'x,y'` `"^(a|b)$"`
+func (p *ruleParser) parseQuoted() (string, *RuleParseError) {
+	start := p.pos
+	quote := p.peek()
+	p.pos++
+
+	var sb strings.Builder
+	for ; !p.eof(); p.pos++ {
+		c := p.peek()
+		if c == '\\' && p.pos+1 < len(p.src) && (p.src[p.pos+1] == quote || p.src[p.pos+1] == '\\') {
+			p.pos++
+			sb.WriteByte(p.src[p.pos])
+			continue
+		}
+
+		if c == quote {
+			p.pos++
+			return sb.String(), nil
+		}
+		sb.WriteByte(c)
+	}
+
+	return "", p.errorf(start, "the quoted argument is not closed")
+}
+
+// hasOrAt check is the " or " separator of the group at the position.
+func (p *ruleParser) hasOrAt(pos int) bool {
+	i := pos
+	for i < len(p.src) && isSpace(p.src[i]) {
+		i++
+	}
+
+	// must have space before the "or". eg: "isEmail or isCnMobile"
+	if i == pos && (pos == 0 || !isSpace(p.src[pos-1])) {
+		return false
+	}
+
+	end := i + 2
+	return end < len(p.src) && p.src[i:end] == "or" && isSpace(p.src[end])
+}
+
+func (p *ruleParser) skipSpaces() {
+	for !p.eof() && isSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *ruleParser) peek() byte {
+	return p.src[p.pos]
+}
+
+func (p *ruleParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+// errorf create an error at the position. the column is count by runes.
+func (p *ruleParser) errorf(pos int, format string, args ...interface{}) *RuleParseError {
+	return &RuleParseError{
+		Rule:    p.src,
+		Column:  utf8.RuneCountInString(p.src[:pos]) + 1,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func isQuote(c byte) bool {
+	return c == '\'' || c == '"'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
@@ -0,0 +1,114 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStringRule(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		rule string
+		want []*ruleItem
+	}{
+		{"required|minLen:6", []*ruleItem{{validator: "required"}, {validator: "minLen", args: []interface{}{"6"}}}},
+		{" |required| between: 1, 10 |", []*ruleItem{{validator: "required"}, {validator: "between", args: []interface{}{"1", "10"}}}},
+		{"required:", []*ruleItem{{validator: "required"}}},
+		// raw argument
+		{`regexp:^\d{4,6}$`, []*ruleItem{{validator: "regexp", args: []interface{}{`^\d{4,6}$`}}}},
+		{`regexp:^(a|b)$|required`, []*ruleItem{{validator: "regexp", args: []interface{}{`^(a|b)$`}}, {validator: "required"}}},
+		{`regexp:^[|,]+$`, []*ruleItem{{validator: "regexp", args: []interface{}{`^[|,]+$`}}}},
+		{`regex:"^(a|b)$"`, []*ruleItem{{validator: "regex", args: []interface{}{`^(a|b)$`}}}},
+		{`default:12:30`, []*ruleItem{{validator: "default", args: []interface{}{"12:30"}}}},
+		// quoted arguments
+		{`in:'x,y','z'`, []*ruleItem{{validator: "in", args: []interface{}{[]string{"x,y", "z"}}}}},
+		{`in:"a\"b",'',c`, []*ruleItem{{validator: "in", args: []interface{}{[]string{`a"b`, "", "c"}}}}},
+		{`date:"2006-01-02 15:04:05"`, []*ruleItem{{validator: "date", args: []interface{}{"2006-01-02 15:04:05"}}}},
+		// escaped
+		{`contains:a\,b\|c`, []*ruleItem{{validator: "contains", args: []interface{}{"a,b|c"}}}},
+		// group
+		{`(isEmail or regexp:^(a|b)$ or in:'x or y',z)`, []*ruleItem{{validator: anyOfName, anyOf: []*ruleItem{
+			{validator: "isEmail"},
+			{validator: "regexp", args: []interface{}{`^(a|b)$`}},
+			{validator: "in", args: []interface{}{[]string{"x or y", "z"}}},
+		}}}},
+	}
+
+	for _, tt := range tests {
+		items, err := parseStringRule(tt.rule)
+		is.Nil(err, tt.rule)
+		is.Equal(tt.want, items, tt.rule)
+	}
+
+	errTests := []struct {
+		rule   string
+		column int
+		msg    string
+	}{
+		{`in:'x,y`, 4, "the quoted argument is not closed"},
+		{`required|in:'x'y`, 16, "unexpected 'y', expect '|'"},
+		{`required|(isEmail or isCnMobile`, 10, "the group is not closed"},
+		{`required|()`, 10, "the group is empty"},
+		{`(isEmail isCnMobile)`, 10, "unexpected 'i', expect 'or' or ')'"},
+		{`(isEmail or (int))`, 13, "the nested group is not supported"},
+		{`required|)`, 10, "unexpected ')'"},
+		{`required|:12`, 10, "missing the validator name"},
+		{`必填|in:"a`, 7, "the quoted argument is not closed"},
+	}
+
+	for _, tt := range errTests {
+		_, err := parseStringRule(tt.rule)
+		if is.NotNil(err, tt.rule) {
+			is.Equal(tt.column, err.Column, tt.rule)
+			is.Equal(tt.msg, err.Message, tt.rule)
+		}
+	}
+}
+
+func TestValidation_StringRule_quoted(t *testing.T) {
+	is := assert.New(t)
+
+	v := Map(M{"code": "b", "tag": "x,y"})
+	v.StringRules(MS{
+		"code": `required|regexp:"^(a|b)$"`,
+		"tag":  `in:'x,y','z'`,
+	})
+	is.True(v.Validate())
+
+	v = Map(M{"code": "c"})
+	v.StringRule("code", `regexp:^(a|b)$`)
+	is.False(v.Validate())
+
+	// invalid rule
+	v = Map(M{"tag": "z"})
+	v.StringRule("tag", `required|in:'x,y`)
+	is.False(v.Validate())
+	is.Equal(`invalid rule "required|in:'x,y" of the field 'tag' at column 13: the quoted argument is not closed`, v.Errors.One())
+
+	var pe *RuleParseError
+	is.True(errorAs(v.Err(), &pe))
+	is.Equal("tag", pe.Field)
+	is.Equal(13, pe.Column)
+
+	// in struct tags
+	type form struct {
+		Tag string `validate:"in:'x,y'|(isEmail"`
+	}
+	v = Struct(&form{Tag: "x,y"})
+	is.False(v.Validate())
+	is.True(errorAs(v.Err(), &pe))
+	is.Equal("Tag", pe.Field)
+	is.Equal(10, pe.Column)
+
+	_, err := StructSchema(&form{})
+	is.True(errorAs(err, &pe))
+
+	s := NewSchema(func(v *Validation) {
+		v.StringRule("tag", `in:'x`)
+	})
+	res := s.Validate(FromMap(M{"tag": "x"}), "")
+	is.True(res.IsFail())
+	is.True(errorAs(res.Err(), &pe))
+}
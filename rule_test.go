@@ -114,3 +114,73 @@ func TestRule_SetSkipEmpty(t *testing.T) {
 	is.False(v.Validate())
 	is.Equal("age value must be an integer and mix value is 1", v.Errors.One())
 }
+
+func TestRule_anyOf(t *testing.T) {
+	is := assert.New(t)
+
+	items, err := parseStringRule("required|(isEmail or isCnMobile)|minLen:5")
+	is.Nil(err)
+	is.Len(items, 3)
+	is.Equal(anyOfName, items[1].validator)
+	is.Len(items[1].anyOf, 2)
+	is.Equal("isCnMobile", items[1].anyOf[1].validator)
+
+	// one alternative is same as a normal validator
+	items, err = parseStringRule("(minLen:5)")
+	is.Nil(err)
+	is.Len(items, 1)
+	is.Equal("minLen", items[0].validator)
+
+	for _, val := range []string{"some@email.com", "18612341234"} {
+		v := Map(M{"account": val})
+		v.StringRule("account", "required|(isEmail or isCnMobile)")
+		is.True(v.Validate())
+		is.Equal(val, v.SafeVal("account"))
+	}
+
+	v := Map(M{"account": "invalid"})
+	v.StringRule("account", "required|(email or isCnMobile or minLen:10)")
+	is.False(v.Validate())
+	is.Equal(
+		"account must pass any of the validators: email, isCnMobile, minLen:10",
+		v.Errors.FieldOne("account"),
+	)
+
+	fe := v.FieldErrors()[0]
+	is.Equal(anyOfName, fe.Validator)
+	is.Equal([]interface{}{"email", "isCnMobile", "minLen:10"}, fe.Args)
+
+	var alts FieldErrors
+	is.True(errorAs(fe, &alts))
+	is.Equal([]string{"email", "isCnMobile", "minLen"}, []string{
+		alts[0].Validator, alts[1].Validator, alts[2].Validator,
+	})
+	is.Equal("account min length is 10", alts[2].Message)
+
+	// custom message for the group
+	v = Map(M{"account": "invalid"})
+	v.StringRule("account", "(isEmail or isCnMobile)")
+	v.AddMessages(MS{"account.anyOf": "account must be an email or a mobile number"})
+	is.False(v.Validate())
+	is.Equal("account must be an email or a mobile number", v.Errors.One())
+
+	// empty value is skipped
+	v = Map(M{"account": ""})
+	v.StringRule("account", "(isEmail or isCnMobile)")
+	is.True(v.Validate())
+
+	// in struct tags
+	type account struct {
+		Contact string `validate:"required|(email or isCnMobile)"`
+	}
+	v = Struct(&account{Contact: "18612341234"})
+	is.True(v.Validate())
+
+	v = Struct(&account{Contact: "abc"})
+	is.False(v.Validate())
+	is.Contains(v.Errors.One(), "isCnMobile")
+
+	is.Panics(func() {
+		Map(M{}).StringRule("name", "(default:abc or minLen:3)")
+	})
+}
@@ -0,0 +1,344 @@
+package validate
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// Schema is a compiled validation rule set. It is immutable after created,
+// one Schema can be shared by all goroutines, every Validate() call has own result state.
+//
+// Usage:
+// 	s := validate.NewSchema(func(v *validate.Validation) {
+// 		v.StringRules(validate.MS{
+// 			"name": "required|minLen:4",
+// 			"age":  "required|int|min:1",
+// 		})
+// 	})
+//
+// 	// in the handlers
+// 	res := s.Validate(validate.FromMap(m), "")
+// 	if res.IsOK() {
+// 		// do something ...
+// 	}
+type Schema struct {
+	// validate rules
+	rules []*Rule
+	// filtering rules
+	filterRules []*FilterRule
+	// scenes config
+	scenes SValues
+	// user set default values
+	defValues map[string]interface{}
+	// readonly message translator
+	trans *Translator
+	// the registry of the global validators, filters
+	reg *Registry
+	// custom validators meta
+	validatorMetas map[string]*funcMeta
+	// custom filter func reflect.Value map
+	filterValues map[string]reflect.Value
+	// the errors of parse the string rules. will add to each validate result.
+	ruleErrors FieldErrors
+	// options, copied from the Validation
+	stopOnError  bool
+	skipOnEmpty  bool
+	updateSource bool
+	checkDefault bool
+	concurrency  int
+	clock        func() time.Time
+	// compare the strings by lexical
+	lexicalCompare bool
+}
+
+// NewSchema create an Schema, you can config the rules in the fn.
+func NewSchema(fn func(v *Validation)) *Schema {
+	v := NewEmpty()
+	fn(v)
+	return v.Compile()
+}
+
+// StructSchema create an Schema from the struct tags.
+// will collect rules from the struct type, so it can be a zero value struct or struct ptr.
+//
+// Usage:
+// 	s, err := validate.StructSchema(&UserForm{})
+// 	// in the handlers
+// 	d, _ := validate.FromStruct(&form)
+// 	res := s.Validate(d, "")
+func StructSchema(s interface{}) (*Schema, error) {
+	v, _, err := structTypeValidation(s)
+	if err != nil {
+		return nil, err
+	}
+
+	v.UpdateSource = true
+	return v.Compile(), nil
+}
+
+// structTypeValidation create an empty Validation with the rules collected from the struct type.
+func structTypeValidation(s interface{}) (*Validation, reflect.Type, error) {
+	if s == nil {
+		return nil, nil, ErrInvalidData
+	}
+
+	vt := removeTypePtr(reflect.TypeOf(s))
+	if vt.Kind() != reflect.Struct || vt == timeType {
+		return nil, nil, ErrInvalidData
+	}
+
+	v := NewEmpty()
+	fMap := make(map[string]string)
+	collectTypeRules(v, optionStructKey(), vt, "", fMap, map[reflect.Type]bool{})
+	// has invalid rules in the tags
+	if err := v.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(fMap) > 0 {
+		v.trans.AddFieldMap(fMap)
+	}
+
+	// call the config methods by a zero value.
+	zero := reflect.New(vt).Elem()
+	if vt.Implements(cvFaceType) {
+		zero.Interface().(ConfigValidationFace).ConfigValidation(v)
+	}
+
+	if vt.Implements(ftFaceType) {
+		v.WithTranslates(zero.Interface().(FieldTranslatorFace).Translates())
+	}
+
+	if vt.Implements(cmFaceType) {
+		v.WithMessages(zero.Interface().(CustomMessagesFace).Messages())
+	}
+	return v, vt, nil
+}
+
+// optionStructKey create the struct cache key by the tag names in the global option.
+// the typ is not set.
+func optionStructKey() structKey {
+	opt := Option()
+	return structKey{
+		validateTag: opt.ValidateTag,
+		filterTag:   opt.FilterTag,
+		fieldTag:    opt.FieldTag,
+		messageTag:  opt.MessageTag,
+		keyTag:      opt.KeyTag,
+	}
+}
+
+// Compile the rules and settings of the Validation to an immutable Schema.
+// the later changes on the Validation will not affect the Schema.
+func (v *Validation) Compile() *Schema {
+	s := &Schema{
+		scenes:    make(SValues, len(v.scenes)),
+		defValues: make(map[string]interface{}, len(v.defValues)),
+		trans:     v.trans.clone(),
+		reg:       v.reg,
+		// custom validators and filters
+		validatorMetas: make(map[string]*funcMeta),
+		filterValues:   make(map[string]reflect.Value, len(v.filterValues)),
+		// options
+		stopOnError:  v.StopOnError,
+		skipOnEmpty:  v.SkipOnEmpty,
+		updateSource: v.UpdateSource,
+		checkDefault: v.CheckDefault,
+		concurrency:  v.Concurrency,
+		clock:        v.Clock,
+		// field compare
+		lexicalCompare: v.LexicalCompare,
+	}
+
+	for _, r := range v.rules {
+		s.rules = append(s.rules, r.clone())
+	}
+
+	for _, fe := range v.fieldErrors {
+		if _, ok := fe.Err.(*RuleParseError); ok {
+			s.ruleErrors = append(s.ruleErrors, fe)
+		}
+	}
+
+	for _, r := range v.filterRules {
+		s.filterRules = append(s.filterRules, r.clone())
+	}
+
+	for scene, fields := range v.scenes {
+		s.scenes[scene] = append([]string(nil), fields...)
+	}
+
+	for field, val := range v.defValues {
+		s.defValues[field] = val
+	}
+
+	for name, typ := range v.validators {
+		if typ == 2 { // custom
+			s.validatorMetas[name] = v.validatorMetas[name]
+		}
+	}
+
+	for name, fv := range v.filterValues {
+		s.filterValues[name] = fv
+	}
+	return s
+}
+
+// Validate the data by the schema rules. will create a new result on each call.
+func (s *Schema) Validate(data DataFace, scene string) *Result {
+	v := s.newValidation(data)
+	if data == nil {
+		v.WithError(ErrEmptyData)
+	}
+
+	v.Validate(scene)
+	return newResult(v)
+}
+
+// ValidateContext validate the data with the context. see Validation.ValidateContext()
+func (s *Schema) ValidateContext(ctx context.Context, data DataFace, scene string) *Result {
+	v := s.newValidation(data)
+	if data == nil {
+		v.WithError(ErrEmptyData)
+	}
+
+	v.ValidateContext(ctx, scene)
+	return newResult(v)
+}
+
+// new Validation for validate data. the rules settings are shared with schema.
+func (s *Schema) newValidation(data DataFace) *Validation {
+	v := newValidationWith(s.reg, data)
+	// NOTICE: the rules, scenes etc. are readonly on validating.
+	// limit the cap, append rules will not change the schema rules.
+	v.rules = s.rules[:len(s.rules):len(s.rules)]
+	v.filterRules = s.filterRules[:len(s.filterRules):len(s.filterRules)]
+	v.scenes = s.scenes
+	v.defValues = s.defValues
+	v.trans = s.trans
+	v.filterValues = s.filterValues
+
+	for name, fm := range s.validatorMetas {
+		v.validators[name] = 2 // custom
+		v.validatorMetas[name] = fm
+	}
+
+	for _, fe := range s.ruleErrors {
+		v.AddFieldError(fe)
+	}
+
+	v.StopOnError = s.stopOnError
+	v.SkipOnEmpty = s.skipOnEmpty
+	v.UpdateSource = s.updateSource
+	v.CheckDefault = s.checkDefault
+	v.Concurrency = s.concurrency
+	v.Clock = s.clock
+	v.LexicalCompare = s.lexicalCompare
+	return v
+}
+
+// Rules get a copy of the schema validate rules
+func (s *Schema) Rules() Rules {
+	rules := make(Rules, 0, len(s.rules))
+	for _, r := range s.rules {
+		rules = append(rules, r.clone())
+	}
+	return rules
+}
+
+/*************************************************************
+ * validate result
+ *************************************************************/
+
+// Result is the validate result of the Schema.Validate()
+type Result struct {
+	// Errors for the validate
+	Errors Errors
+	// structured errors for the validate
+	fieldErrors FieldErrors
+	scene       string
+	// validated safe data
+	safeData M
+	// filtered clean data
+	filteredData M
+}
+
+func newResult(v *Validation) *Result {
+	return &Result{
+		Errors:      v.Errors,
+		fieldErrors: v.fieldErrors,
+		scene:       v.scene,
+		// result data
+		safeData:     v.safeData,
+		filteredData: v.filteredData,
+	}
+}
+
+// IsOK for the validate
+func (r *Result) IsOK() bool {
+	return r.Errors.Empty()
+}
+
+// IsFail for the validate
+func (r *Result) IsFail() bool {
+	return !r.Errors.Empty()
+}
+
+// FieldErrors get all structured errors
+func (r *Result) FieldErrors() FieldErrors {
+	return r.fieldErrors
+}
+
+// Err returns the validate error. it is a FieldErrors on has error, otherwise nil.
+func (r *Result) Err() error {
+	if r.fieldErrors.Empty() {
+		return nil
+	}
+	return r.fieldErrors
+}
+
+// Scene name of the validate
+func (r *Result) Scene() string {
+	return r.scene
+}
+
+// Safe get safe value by key
+func (r *Result) Safe(key string) (val interface{}, ok bool) {
+	val, ok = r.safeData[key]
+	return
+}
+
+// SafeVal get safe value by key
+func (r *Result) SafeVal(key string) interface{} {
+	return r.safeData[key]
+}
+
+// SafeData get all validated safe data
+func (r *Result) SafeData() M {
+	return r.safeData
+}
+
+// Filtered get filtered value by key
+func (r *Result) Filtered(key string) interface{} {
+	return r.filteredData[key]
+}
+
+// FilteredData return filtered data.
+func (r *Result) FilteredData() M {
+	return r.filteredData
+}
+
+// BindSafeData binding safe data to an struct.
+func (r *Result) BindSafeData(ptr interface{}) error {
+	if len(r.safeData) == 0 { // no safe data.
+		return nil
+	}
+
+	// to json bytes
+	bts, err := Marshal(r.safeData)
+	if err != nil {
+		return err
+	}
+
+	return Unmarshal(bts, ptr)
+}
@@ -0,0 +1,140 @@
+package validate
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSchema(t *testing.T) {
+	is := assert.New(t)
+
+	v := NewEmpty()
+	v.StringRules(MS{
+		"name": "required|minLen:4",
+		"age":  "required|int|min:1",
+	})
+	v.FilterRule("name", "trim")
+	v.AddValidator("notAdmin", func(s string) bool {
+		return s != "admin"
+	})
+	v.StringRule("name", "notAdmin")
+	v.AddMessages(MS{"age.min": "age is too small"})
+	v.WithScenes(SValues{"update": {"name"}})
+	s := v.Compile()
+
+	// change the validation will not affect the schema
+	v.StringRule("email", "required|email")
+	is.Len(s.Rules(), 6)
+
+	res := s.Validate(FromMap(M{"name": " inhere ", "age": 23}), "")
+	is.True(res.IsOK())
+	is.Equal("inhere", res.SafeVal("name"))
+	is.Equal("inhere", res.Filtered("name"))
+	is.Equal(23, res.SafeVal("age"))
+
+	res = s.Validate(FromMap(M{"name": "admin", "age": 20}), "")
+	is.True(res.IsFail())
+	is.Equal("name field did not pass validation", res.Errors.FieldOne("name"))
+	is.Empty(res.SafeData())
+
+	s1 := NewSchema(func(v *Validation) {
+		v.StopOnError = false
+		v.AddRule("age", "min", 18).SetMessage("age is too small")
+	})
+	res = s1.Validate(FromMap(M{"age": 12}), "")
+	is.Equal("age is too small", res.Errors.One())
+	is.Equal("min", res.FieldErrors()[0].Validator)
+	is.Equal(12, res.FieldErrors()[0].Value)
+	is.Error(res.Err())
+
+	res = s1.Validate(nil, "")
+	is.True(res.IsFail())
+	is.Contains(res.Errors.String(), ErrEmptyData.Error())
+
+	// scene
+	res = s.Validate(FromMap(M{"name": "inhere"}), "update")
+	is.True(res.IsOK())
+	is.Equal("update", res.Scene())
+
+	u := &struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}{}
+	is.NoError(res.BindSafeData(u))
+	is.Equal("inhere", u.Name)
+}
+
+func TestStructSchema(t *testing.T) {
+	is := assert.New(t)
+
+	_, err := StructSchema(nil)
+	is.Equal(ErrInvalidData, err)
+	_, err = StructSchema("invalid")
+	is.Equal(ErrInvalidData, err)
+
+	s, err := StructSchema(UserForm{})
+	is.NoError(err)
+
+	u := &UserForm{Name: "inhere"}
+	d, err := FromStruct(u)
+	is.NoError(err)
+
+	res := s.Validate(d, "")
+	is.True(res.IsFail())
+	is.Equal("User Name min length is 7", res.Errors.FieldOne("Name"))
+
+	u = &UserForm{Name: "new name", Code: "abcd", Status: 3, UpdateAt: time.Now()}
+	d, _ = FromStruct(u)
+	res = s.Validate(d, "")
+	is.True(res.IsFail())
+	is.Equal("Status value must be greater the field Extra.0.Status1", res.Errors.One())
+
+	// sub struct and struct ptr
+	s, err = StructSchema(&User3{})
+	is.NoError(err)
+	age := 3
+	u3 := &User3{In2: &Info2{Org: Org{Company: "A"}, Sub: &Info{Email: " SOME@163.com ", Age: &age}}}
+	d, _ = FromStruct(u3)
+	res = s.Validate(d, "")
+	is.True(res.IsOK())
+	is.Equal("some@163.com", u3.In2.Sub.Email)
+
+	// recursive struct type
+	type Node struct {
+		Name string `validate:"required"`
+		Next *Node
+	}
+	s, err = StructSchema(&Node{})
+	is.NoError(err)
+	is.Len(s.Rules(), 1)
+}
+
+func TestSchema_concurrent(t *testing.T) {
+	s := NewSchema(func(v *Validation) {
+		v.StringRules(MS{
+			"name": "required|minLen:4",
+			"age":  "required|int|between:1,99",
+		})
+	})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			res := s.Validate(FromMap(M{"name": "inhere", "age": i}), "")
+			if i == 0 {
+				assert.Equal(t, "age is required and not empty", res.Errors.One())
+			} else {
+				assert.True(t, res.IsOK(), fmt.Sprint(i))
+				assert.Equal(t, i, res.SafeVal("age"))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
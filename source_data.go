@@ -0,0 +1,333 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+/*************************************************************
+ * Source positions
+ *************************************************************/
+
+// Position is the source position of a field value in the document.
+type Position struct {
+	// File the source file name. eg: "config.yaml"
+	File string
+	// Offset the byte offset, starts from 0. it is -1 if unknown.
+	Offset int
+	// Line number, starts from 1.
+	Line int
+	// Column number, starts from 1. it is counted by the characters, not bytes.
+	Column int
+}
+
+// String get the position string. eg: "config.yaml:42:7", "42:7"
+func (p Position) String() string {
+	s := strconv.Itoa(p.Line) + ":" + strconv.Itoa(p.Column)
+	if p.File != "" {
+		return p.File + ":" + s
+	}
+	return s
+}
+
+// PositionFace the data source can report the source positions of the fields.
+// the FieldError.Pos will be set on the data source implements it.
+type PositionFace interface {
+	Position(field string) (Position, bool)
+}
+
+// sourceText find the line and column by the offset of the source.
+type sourceText struct {
+	src []byte
+	// the offsets of the line starts
+	lines []int
+}
+
+func newSourceText(src []byte) *sourceText {
+	lines := []int{0}
+	for i, b := range src {
+		if b == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return &sourceText{src: src, lines: lines}
+}
+
+// position of the byte offset
+func (st *sourceText) position(offset int) Position {
+	line := sort.Search(len(st.lines), func(i int) bool {
+		return st.lines[i] > offset
+	})
+
+	start := st.lines[line-1]
+	return Position{
+		Offset: offset,
+		Line:   line,
+		Column: utf8.RuneCount(st.src[start:offset]) + 1,
+	}
+}
+
+// position of the line and column(starts from 1)
+func (st *sourceText) lineColumn(line, column int) Position {
+	if line < 1 || line > len(st.lines) {
+		return Position{Offset: -1, Line: line, Column: column}
+	}
+
+	offset := st.lines[line-1]
+	for i := 1; i < column && offset < len(st.src); i++ {
+		_, size := utf8.DecodeRune(st.src[offset:])
+		offset += size
+	}
+	return Position{Offset: offset, Line: line, Column: column}
+}
+
+/*************************************************************
+ * Source Data
+ *************************************************************/
+
+// SourceData is the map data decoded from a source document, like JSON, YAML, TOML.
+// it keeps the nested field paths and the source positions of them.
+//
+// Usage:
+// 	d, err := validate.FromYAML(bs)
+// 	d.File = "config.yaml"
+//
+// 	v := d.Create()
+// 	v.StringRules(validate.MS{"server.port": "required|int|max:65535"})
+// 	if !v.Validate() {
+// 		for _, fe := range v.FieldErrors() {
+// 			// eg: "config.yaml:42: server.port max value is 65535"
+// 			fmt.Printf("%s:%d: %s\n", fe.Pos.File, fe.Pos.Line, fe.Message)
+// 		}
+// 	}
+type SourceData struct {
+	MapData
+	// File the source file name, will set to the error positions.
+	File string
+	// field path to the position of the key(YAML, TOML) or value(JSON).
+	// eg: {"server.port": {Line: 42, Column: 3}}
+	positions map[string]Position
+}
+
+func newSourceData(m map[string]interface{}) *SourceData {
+	return &SourceData{
+		MapData:   MapData{Map: m, value: reflect.ValueOf(m)},
+		positions: make(map[string]Position),
+	}
+}
+
+// Create a Validation from data
+func (d *SourceData) Create(err ...error) *Validation {
+	return d.Validation(err...)
+}
+
+// Validation create from data
+func (d *SourceData) Validation(err ...error) *Validation {
+	if len(err) > 0 {
+		return NewValidation(d).WithError(err[0])
+	}
+	return NewValidation(d)
+}
+
+// Position get the source position of the field.
+// if the field is not exists, will return the position of the nearest parent.
+// eg: "server.port" not exists, return the position of the "server".
+func (d *SourceData) Position(field string) (Position, bool) {
+	for field != "" {
+		if pos, ok := d.positions[field]; ok {
+			pos.File = d.File
+			return pos, true
+		}
+
+		idx := strings.LastIndexByte(field, '.')
+		if idx < 0 {
+			break
+		}
+		field = field[:idx]
+	}
+	return Position{}, false
+}
+
+// FromJSONSource build the data instance from the JSON bytes, like the FromJSONBytes(),
+// but it keeps the source positions of the values for the errors. see FieldError.Pos
+//
+// Usage:
+// 	d, err := validate.FromJSONSource(body)
+// 	v := d.Create()
+// 	v.StringRules(validate.MS{"items.*.price": "required|float|min:0"})
+// 	if !v.Validate() {
+// 		fe := v.FieldErrors()[0]
+// 		fmt.Println(fe.Pos.Offset, fe.Pos.Line, fe.Pos.Column) // eg: 1024 42 18
+// 	}
+func FromJSONSource(bs []byte) (*SourceData, error) {
+	mp := map[string]interface{}{}
+	if err := unmarshalJSON(bs, &mp, Option().UseNumber); err != nil {
+		return nil, err
+	}
+
+	d := newSourceData(mp)
+	d.bodyJSON = bs
+
+	s := &jsonScanner{src: bs, text: newSourceText(bs), positions: d.positions}
+	s.skipSpace()
+	s.scanValue("")
+	return d, nil
+}
+
+// jsonScanner scan the positions of the values in the valid JSON.
+type jsonScanner struct {
+	src  []byte
+	off  int
+	text *sourceText
+	// field path to the position of the value
+	positions map[string]Position
+}
+
+func (s *jsonScanner) skipSpace() {
+	for s.off < len(s.src) && strings.IndexByte(" \t\r\n", s.src[s.off]) >= 0 {
+		s.off++
+	}
+}
+
+// scan the value at the offset, and record the positions of the children.
+func (s *jsonScanner) scanValue(path string) {
+	switch s.src[s.off] {
+	case '{':
+		s.off++
+		for {
+			s.skipSpace()
+			if s.src[s.off] == '}' {
+				s.off++
+				return
+			}
+
+			key := s.scanString()
+			s.skipSpace()
+			s.off++ // skip ':'
+			s.skipSpace()
+			s.scanChild(joinFieldPath(path, key))
+		}
+	case '[':
+		s.off++
+		for i := 0; ; i++ {
+			s.skipSpace()
+			if s.src[s.off] == ']' {
+				s.off++
+				return
+			}
+			s.scanChild(joinFieldPath(path, strconv.Itoa(i)))
+		}
+	case '"':
+		s.scanString()
+	default: // number, true, false, null
+		for s.off < len(s.src) && strings.IndexByte(",]} \t\r\n", s.src[s.off]) < 0 {
+			s.off++
+		}
+	}
+}
+
+// scan the object property or array element, and skip the ',' after it.
+func (s *jsonScanner) scanChild(path string) {
+	s.positions[path] = s.text.position(s.off)
+	s.scanValue(path)
+	s.skipSpace()
+	if s.src[s.off] == ',' {
+		s.off++
+	}
+}
+
+// scan the string and return the unquoted value.
+func (s *jsonScanner) scanString() string {
+	start := s.off
+	escaped := false
+	for s.off++; s.src[s.off] != '"'; s.off++ {
+		if s.src[s.off] == '\\' {
+			escaped = true
+			s.off++
+		}
+	}
+
+	s.off++
+	raw := s.src[start:s.off]
+	if !escaped {
+		return string(raw[1 : len(raw)-1])
+	}
+
+	var str string
+	_ = json.Unmarshal(raw, &str)
+	return str
+}
+
+// FromYAML build the data instance from the YAML document, the root must be a mapping.
+// the source positions of the fields are kept for the errors. see FieldError.Pos
+func FromYAML(bs []byte) (*SourceData, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(bs, &doc); err != nil {
+		return nil, err
+	}
+
+	d := newSourceData(map[string]interface{}{})
+	if len(doc.Content) == 0 { // empty document
+		return d, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("validate: the YAML document must be a mapping")
+	}
+
+	// the values are decoded by the yaml, it handles the tags, anchors and merge keys.
+	if err := root.Decode(&d.Map); err != nil {
+		return nil, err
+	}
+
+	d.value = reflect.ValueOf(d.Map)
+	d.addYAMLPositions(newSourceText(bs), "", root)
+	return d, nil
+}
+
+// add the positions of the mapping keys and sequence items.
+// the aliases are not expanded, the fields in them use the position of the alias.
+func (d *SourceData) addYAMLPositions(st *sourceText, path string, n *yaml.Node) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			if key.Tag == "!!merge" {
+				continue
+			}
+
+			field := joinFieldPath(path, key.Value)
+			d.positions[field] = st.lineColumn(key.Line, key.Column)
+			d.addYAMLPositions(st, field, val)
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			field := joinFieldPath(path, strconv.Itoa(i))
+			d.positions[field] = st.lineColumn(item.Line, item.Column)
+			d.addYAMLPositions(st, field, item)
+		}
+	}
+}
+
+// FromTOML build the data instance from the TOML(v1.0) document.
+// the source positions of the fields are kept for the errors. see FieldError.Pos
+//
+// the offset date-time is decoded as time.Time, the local date-time, date and time are kept as string.
+func FromTOML(bs []byte) (*SourceData, error) {
+	p := newTOMLParser(bs)
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+
+	d := newSourceData(p.root)
+	d.positions = p.positions
+	return d, nil
+}
@@ -0,0 +1,203 @@
+package validate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var yamlConfig = `# the app config
+name: demo
+server:
+  host: "localhost"
+  port: 70000
+  tls: &tls
+    enable: true
+db:
+  - name: main
+    dsn: "mysql://localhost"
+  - name: replica
+backup:
+  <<: *tls
+  dir: /tmp
+`
+
+func TestFromYAML(t *testing.T) {
+	is := assert.New(t)
+
+	d, err := FromYAML([]byte(yamlConfig))
+	is.NoError(err)
+	d.File = "config.yaml"
+
+	val, ok := d.Get("server.port")
+	is.True(ok)
+	is.Equal(70000, val)
+	val, _ = d.Get("db.1.name")
+	is.Equal("replica", val)
+	// merge key
+	val, _ = d.Get("backup.enable")
+	is.Equal(true, val)
+
+	pos, ok := d.Position("server.port")
+	is.True(ok)
+	is.Equal(Position{File: "config.yaml", Offset: 58, Line: 5, Column: 3}, pos)
+	is.Equal("config.yaml:5:3", pos.String())
+	is.Equal("port: 70000", yamlConfig[pos.Offset:pos.Offset+11])
+
+	// the nearest parent
+	pos, _ = d.Position("db.1.dsn")
+	is.Equal(11, pos.Line)
+	_, ok = d.Position("not-exists")
+	is.False(ok)
+
+	v := d.Create()
+	v.StopOnError = false
+	v.StringRules(MS{
+		"name":        "required|minLen:2",
+		"server.port": "required|int|between:1,65535",
+		"db.*.dsn":    "required|string",
+	})
+	is.False(v.Validate())
+
+	var lines []string
+	for _, fe := range v.FieldErrors() {
+		lines = append(lines, fmt.Sprintf("%s:%d: %s", fe.Pos.File, fe.Pos.Line, fe.Message))
+	}
+	is.Equal([]string{
+		"config.yaml:11: db.1.dsn is required and not empty",
+		"config.yaml:5: server.port value must be in the range 1 - 65535",
+	}, lines)
+
+	// empty
+	d, err = FromYAML(nil)
+	is.NoError(err)
+	is.Empty(d.Map)
+
+	_, err = FromYAML([]byte("- a\n- b"))
+	is.Error(err)
+	_, err = FromYAML([]byte("a: [b"))
+	is.Error(err)
+}
+
+var tomlConfig = `# the app config
+name = "demo"
+
+[server]
+host = "localhost"
+port = 70000
+tls.enable = true
+
+[[db]]
+name = "main"
+dsn = "mysql://localhost"
+
+[[db]]
+name = "replica"
+`
+
+func TestFromTOML(t *testing.T) {
+	is := assert.New(t)
+
+	d, err := FromTOML([]byte(tomlConfig))
+	is.NoError(err)
+	d.File = "config.toml"
+
+	val, _ := d.Get("server.port")
+	is.Equal(int64(70000), val)
+	val, _ = d.Get("server.tls.enable")
+	is.Equal(true, val)
+	val, _ = d.Get("db.1.name")
+	is.Equal("replica", val)
+
+	pos, ok := d.Position("server.port")
+	is.True(ok)
+	is.Equal(Position{File: "config.toml", Offset: 60, Line: 6, Column: 1}, pos)
+
+	v := d.Create()
+	v.StopOnError = false
+	v.StringRules(MS{
+		"server.port": "required|int|between:1,65535",
+		"db.*.dsn":    "required",
+	})
+	is.False(v.Validate())
+
+	fes := v.FieldErrors()
+	is.Len(fes, 2)
+	is.Equal("db.1.dsn", fes[0].Field)
+	is.Equal("config.toml:13:1", fes[0].Pos.String())
+	is.Equal("server.port", fes[1].Field)
+	is.Equal("config.toml:6:1", fes[1].Pos.String())
+
+	// the error from the schema
+	s := NewSchema(func(v *Validation) {
+		v.StringRules(MS{"server.port": "max:65535"})
+	})
+	res := s.Validate(d, "")
+	is.Equal(6, res.FieldErrors()[0].Pos.Line)
+
+	// other data source without positions
+	res = s.Validate(FromMap(M{"server": M{"port": 70000}}), "")
+	is.Nil(res.FieldErrors()[0].Pos)
+
+	_, err = FromTOML([]byte("a = "))
+	is.Error(err)
+}
+
+func TestFromJSONSource(t *testing.T) {
+	is := assert.New(t)
+
+	src := `{
+  "name": "inhere",
+  "items": [
+    {"id": 1, "price": 10},
+    {"id": 2, "price": -1, "tags": ["a", "b"]}
+  ],
+  "desc": "\u4e2d\u6587",
+  "k\"ey": {"sub": null}
+}`
+	d, err := FromJSONSource([]byte(src))
+	is.NoError(err)
+
+	val, _ := d.Get("items.1.price")
+	is.Equal(float64(-1), val)
+	val, _ = d.Get("desc")
+	is.Equal("中文", val)
+
+	pos, ok := d.Position("items.1.price")
+	is.True(ok)
+	is.Equal(Position{Offset: 86, Line: 5, Column: 24}, pos)
+	is.Equal("-1", src[pos.Offset:pos.Offset+2])
+
+	pos, _ = d.Position("items.1.tags.1")
+	is.Equal(`"b"`, src[pos.Offset:pos.Offset+3])
+	pos, _ = d.Position(`k"ey.sub`)
+	is.Equal("null", src[pos.Offset:pos.Offset+4])
+
+	v := d.Create()
+	v.StringRules(MS{"items.*.price": "required|min:0"})
+	is.False(v.Validate())
+
+	fe := v.FieldErrors()[0]
+	is.Equal("items.1.price", fe.Field)
+	is.Equal(86, fe.Pos.Offset)
+	is.Equal("5:24", fe.Pos.String())
+
+	// the missing field use the parent position
+	v = d.Create()
+	v.StringRules(MS{"items.0.tags": "required"})
+	is.False(v.Validate())
+	is.Equal(4, v.FieldErrors()[0].Pos.Line)
+
+	// bind the source JSON
+	st := &struct {
+		Name string `json:"name"`
+	}{}
+	is.NoError(d.BindJSON(st))
+	is.Equal("inhere", st.Name)
+
+	_, err = FromJSONSource([]byte(`{"name": `))
+	is.Error(err)
+	_, err = FromJSONSource([]byte(`[1, 2]`))
+	is.Error(err)
+}
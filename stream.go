@@ -0,0 +1,183 @@
+package validate
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DefaultStreamMaxErrors the default max number of the collected errors in the StreamResult.Errors
+const DefaultStreamMaxErrors = 1000
+
+// StreamOption for the Schema.ValidateStream()
+type StreamOption struct {
+	// Scene name for the validate
+	Scene string
+	// MaxFailures stop the validating after N records failed. 0 is no limit.
+	MaxFailures int
+	// MaxErrors the max number of the collected errors in the StreamResult.Errors, the more failed records
+	// are only counted by the StreamResult.Failed. default is DefaultStreamMaxErrors, -1 is no limit.
+	MaxErrors int
+	// OnRecord will be called after each record validated, return false to stop the validating.
+	// if it is set, the failed records are not collected to the StreamResult.Errors.
+	OnRecord func(index int, res *Result) bool
+}
+
+// StreamResult is the result of the Schema.ValidateStream()
+type StreamResult struct {
+	// Total the number of the validated records
+	Total int
+	// Failed the number of the failed records
+	Failed int
+	// Errors the errors of the failed records, the order is same as the records.
+	// NOTICE: only the first StreamOption.MaxErrors errors are collected.
+	Errors RecordErrors
+}
+
+// IsOK all records are valid
+func (sr *StreamResult) IsOK() bool {
+	return sr.Failed == 0
+}
+
+// RecordError is the validate errors of a record in the stream.
+type RecordError struct {
+	// Index of the record, starts from 0.
+	Index int
+	// Errors the structured errors of the record
+	Errors FieldErrors
+}
+
+// Error string get. eg: "record #3: name is required; age min value is 1"
+func (re *RecordError) Error() string {
+	msgs := make([]string, 0, len(re.Errors))
+	for _, fe := range re.Errors {
+		msgs = append(msgs, fe.Message)
+	}
+	return fmt.Sprintf("record #%d: %s", re.Index, strings.Join(msgs, "; "))
+}
+
+// RecordErrors list
+type RecordErrors []*RecordError
+
+// Error string get
+func (res RecordErrors) Error() string {
+	var sb strings.Builder
+	for i, re := range res {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(re.Error())
+	}
+	return sb.String()
+}
+
+// ValidateStream validate the records of a JSON array or NDJSON(newline-delimited JSON) stream.
+// each record must be a JSON object, it is decoded and validated one by one, so the memory is bounded.
+// the collected errors are limited by the StreamOption.MaxErrors, default is DefaultStreamMaxErrors.
+//
+// the returned error is the read or JSON syntax error, the validate errors are in the StreamResult.
+//
+// Usage:
+// 	s := validate.NewSchema(func(v *validate.Validation) {
+// 		v.StringRules(validate.MS{"name": "required", "age": "int|min:1"})
+// 	})
+//
+// 	sr, err := s.ValidateStream(r.Body, func(opt *validate.StreamOption) {
+// 		opt.MaxFailures = 100
+// 	})
+// 	for _, re := range sr.Errors {
+// 		fmt.Println(re.Index, re.Errors.One())
+// 	}
+func (s *Schema) ValidateStream(r io.Reader, fns ...func(opt *StreamOption)) (*StreamResult, error) {
+	opt := &StreamOption{MaxErrors: DefaultStreamMaxErrors}
+	for _, fn := range fns {
+		fn(opt)
+	}
+
+	br := bufio.NewReader(r)
+	isArray, err := isJSONArrayStream(br)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(br)
+	if Option().UseNumber {
+		dec.UseNumber()
+	}
+
+	// skip the '['
+	if isArray {
+		if _, err = dec.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	sr := &StreamResult{}
+	for {
+		if isArray && !dec.More() {
+			break
+		}
+
+		var mp map[string]interface{}
+		err = dec.Decode(&mp)
+		if err == io.EOF && !isArray {
+			return sr, nil
+		}
+
+		v := s.newValidation(FromMap(mp))
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			// the record is not an object, the decoder can continue.
+			v.WithError(fmt.Errorf("the record must be a JSON object: %s", err.Error()))
+		} else if err != nil {
+			return sr, fmt.Errorf("validate: decode the record #%d error: %s", sr.Total, err.Error())
+		} else {
+			v.Validate(opt.Scene)
+		}
+
+		if sr.addRecord(opt, v) {
+			return sr, nil
+		}
+	}
+
+	// the ']'
+	if _, err = dec.Token(); err != nil {
+		return sr, err
+	}
+	return sr, nil
+}
+
+// add the validated record, return true on should stop the validating.
+func (sr *StreamResult) addRecord(opt *StreamOption, v *Validation) (stop bool) {
+	index := sr.Total
+	sr.Total++
+	if v.hasError {
+		sr.Failed++
+		if opt.OnRecord == nil && (opt.MaxErrors < 0 || len(sr.Errors) < opt.MaxErrors) {
+			sr.Errors = append(sr.Errors, &RecordError{Index: index, Errors: v.fieldErrors})
+		}
+	}
+
+	if opt.OnRecord != nil && !opt.OnRecord(index, newResult(v)) {
+		return true
+	}
+	return opt.MaxFailures > 0 && sr.Failed >= opt.MaxFailures
+}
+
+// check the stream is a JSON array by the first non-space char.
+func isJSONArrayStream(br *bufio.Reader) (bool, error) {
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if strings.IndexByte(" \t\r\n", c) < 0 {
+			return c == '[', br.UnreadByte()
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_ValidateStream(t *testing.T) {
+	is := assert.New(t)
+
+	s := NewSchema(func(v *Validation) {
+		v.StringRules(MS{"name": "required", "age": "min:1"})
+	})
+
+	// JSON array
+	sr, err := s.ValidateStream(strings.NewReader(` [
+		{"name": "inhere", "age": 20},
+		{"age": 20},
+		{"name": "tom", "age": -1},
+		[1, 2],
+		{"name": "jerry"}
+	]`))
+	is.NoError(err)
+	is.Equal(5, sr.Total)
+	is.Equal(3, sr.Failed)
+	is.False(sr.IsOK())
+	is.Len(sr.Errors, 3)
+	is.Equal(1, sr.Errors[0].Index)
+	is.Equal("name", sr.Errors[0].Errors[0].Field)
+	is.Equal(2, sr.Errors[1].Index)
+	is.Equal("age", sr.Errors[1].Errors[0].Field)
+	is.Equal(3, sr.Errors[2].Index)
+	is.Equal(validateError, sr.Errors[2].Errors[0].Field)
+	is.Equal("record #1: name is required and not empty", sr.Errors[0].Error())
+	is.Contains(sr.Errors.Error(), "\nrecord #2: ")
+
+	// NDJSON
+	ndjson := `{"name": "inhere", "age": 20}
+{"age": 20}
+{"name": "tom", "age": -1}
+{"name": "jerry"}
+`
+	sr, err = s.ValidateStream(strings.NewReader(ndjson))
+	is.NoError(err)
+	is.Equal(4, sr.Total)
+	is.Equal(2, sr.Failed)
+
+	// stop after N failures
+	sr, err = s.ValidateStream(strings.NewReader(ndjson), func(opt *StreamOption) {
+		opt.MaxFailures = 1
+	})
+	is.NoError(err)
+	is.Equal(2, sr.Total)
+	is.Equal(1, sr.Failed)
+
+	// limit the collected errors
+	sr, err = s.ValidateStream(strings.NewReader(ndjson), func(opt *StreamOption) {
+		opt.MaxErrors = 1
+	})
+	is.NoError(err)
+	is.Equal(4, sr.Total)
+	is.Equal(2, sr.Failed)
+	is.Len(sr.Errors, 1)
+	is.Equal(1, sr.Errors[0].Index)
+
+	sr, err = s.ValidateStream(strings.NewReader(strings.Repeat(`{"age": 0}`+"\n", DefaultStreamMaxErrors+2)))
+	is.NoError(err)
+	is.Equal(DefaultStreamMaxErrors+2, sr.Failed)
+	is.Len(sr.Errors, DefaultStreamMaxErrors)
+
+	sr, err = s.ValidateStream(strings.NewReader(strings.Repeat(`{"age": 0}`+"\n", DefaultStreamMaxErrors+2)), func(opt *StreamOption) {
+		opt.MaxErrors = -1
+	})
+	is.NoError(err)
+	is.Len(sr.Errors, DefaultStreamMaxErrors+2)
+
+	// the callback
+	var indexes []int
+	sr, err = s.ValidateStream(strings.NewReader(ndjson), func(opt *StreamOption) {
+		opt.OnRecord = func(index int, res *Result) bool {
+			if res.IsFail() {
+				indexes = append(indexes, index)
+			}
+			return index < 2
+		}
+	})
+	is.NoError(err)
+	is.Equal([]int{1, 2}, indexes)
+	is.Equal(3, sr.Total)
+	is.Empty(sr.Errors)
+
+	// empty
+	sr, err = s.ValidateStream(strings.NewReader(" "))
+	is.NoError(err)
+	is.True(sr.IsOK())
+	sr, err = s.ValidateStream(strings.NewReader("[]"))
+	is.NoError(err)
+	is.Equal(0, sr.Total)
+
+	// syntax error
+	sr, err = s.ValidateStream(strings.NewReader(`{"name": "inhere"}
+{"name": `))
+	is.Error(err)
+	is.Contains(err.Error(), "record #1")
+	is.Equal(1, sr.Total)
+
+	_, err = s.ValidateStream(strings.NewReader(`[{"name": "inhere"}`))
+	is.Error(err)
+}
@@ -0,0 +1,653 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// the kinds of the TOML tables and values, for check the redefinition.
+const (
+	// created by the parent of a table header. eg: "a" for "[a.b]"
+	tomlImplicit uint8 = iota + 1
+	// created by the dotted keys. eg: "a" for "a.b = 1"
+	tomlDotted
+	// defined by the table header. eg: "[a]"
+	tomlTable
+	// defined by the array of tables header. eg: "[[a]]"
+	tomlArray
+	// the values and inline tables, cannot be extended.
+	tomlValue
+)
+
+var (
+	rxTOMLInt       = regexp.MustCompile(`^[+-]?(0|[1-9](_?\d)*)$`)
+	rxTOMLPrefixInt = regexp.MustCompile(`^0(x[\da-fA-F](_?[\da-fA-F])*|o[0-7](_?[0-7])*|b[01](_?[01])*)$`)
+	rxTOMLFloat     = regexp.MustCompile(`^[+-]?(0|[1-9](_?\d)*)(\.\d(_?\d)*)?([eE][+-]?\d(_?\d)*)?$`)
+	rxTOMLDate      = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// tomlParser is a TOML(v1.0) parser, it records the positions of the keys.
+type tomlParser struct {
+	src  []byte
+	off  int
+	text *sourceText
+	// the parsed data and the positions of the field paths
+	root      map[string]interface{}
+	positions map[string]Position
+	// field path to the kind. eg: {"servers.0": tomlTable}
+	kinds map[string]uint8
+	// the current table and the field path of it.
+	cur     map[string]interface{}
+	curPath string
+}
+
+func newTOMLParser(src []byte) *tomlParser {
+	root := make(map[string]interface{})
+	return &tomlParser{
+		src:       src,
+		text:      newSourceText(src),
+		root:      root,
+		cur:       root,
+		positions: make(map[string]Position),
+		kinds:     make(map[string]uint8),
+	}
+}
+
+func (p *tomlParser) parse() error {
+	if !utf8.Valid(p.src) {
+		return p.errorf("the document must be UTF-8 encoded")
+	}
+
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil
+		}
+
+		var err error
+		if p.peek() == '[' {
+			err = p.parseTable()
+		} else {
+			err = p.parseKeyValue(p.cur, p.curPath)
+		}
+
+		if err != nil {
+			return err
+		}
+		if err = p.endLine(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	pos := p.text.position(p.off)
+	return fmt.Errorf("toml: line %d, column %d: %s", pos.Line, pos.Column, fmt.Sprintf(format, args...))
+}
+
+func (p *tomlParser) eof() bool {
+	return p.off >= len(p.src)
+}
+
+func (p *tomlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.off]
+}
+
+func (p *tomlParser) hasPrefix(s string) bool {
+	return bytes.HasPrefix(p.src[p.off:], []byte(s))
+}
+
+// skip the spaces and tabs
+func (p *tomlParser) skipSpace() {
+	for !p.eof() && (p.src[p.off] == ' ' || p.src[p.off] == '\t') {
+		p.off++
+	}
+}
+
+// skip the comment to the end of line
+func (p *tomlParser) skipComment() {
+	if p.peek() != '#' {
+		return
+	}
+
+	for !p.eof() && p.src[p.off] != '\n' {
+		p.off++
+	}
+}
+
+// skip the spaces, comments and newlines
+func (p *tomlParser) skipBlank() {
+	for {
+		p.skipSpace()
+		p.skipComment()
+		if p.hasPrefix("\n") {
+			p.off++
+		} else if p.hasPrefix("\r\n") {
+			p.off += 2
+		} else {
+			return
+		}
+	}
+}
+
+// the line must be end after a key/value or table header
+func (p *tomlParser) endLine() error {
+	p.skipSpace()
+	p.skipComment()
+	switch {
+	case p.eof():
+	case p.hasPrefix("\n"):
+		p.off++
+	case p.hasPrefix("\r\n"):
+		p.off += 2
+	default:
+		return p.errorf("expected the end of line, but got %q", p.peek())
+	}
+	return nil
+}
+
+func (p *tomlParser) expect(s string) error {
+	if !p.hasPrefix(s) {
+		return p.errorf("expected %q", s)
+	}
+
+	p.off += len(s)
+	return nil
+}
+
+// parse the dotted key. eg: `a.b`, `"a b".c`
+func (p *tomlParser) parseKey() ([]string, error) {
+	var keys []string
+	for {
+		p.skipSpace()
+
+		var key string
+		var err error
+		switch p.peek() {
+		case '"':
+			key, err = p.parseBasicString()
+		case '\'':
+			key, err = p.parseLiteralString()
+		default:
+			start := p.off
+			for !p.eof() && isTOMLBareKeyChar(p.src[p.off]) {
+				p.off++
+			}
+
+			if start == p.off {
+				return nil, p.errorf("invalid key")
+			}
+			key = string(p.src[start:p.off])
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// the key cannot be used in the field path
+		if key == "" || strings.ContainsAny(key, ".*") {
+			return nil, p.errorf("the key '%s' cannot be used as the field path", key)
+		}
+
+		keys = append(keys, key)
+		p.skipSpace()
+		if p.peek() != '.' {
+			return keys, nil
+		}
+		p.off++
+	}
+}
+
+func isTOMLBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// parse the table header. eg: "[a.b]", "[[products]]"
+func (p *tomlParser) parseTable() error {
+	pos := p.text.position(p.off)
+	isArray := p.hasPrefix("[[")
+	if isArray {
+		p.off += 2
+	} else {
+		p.off++
+	}
+
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+
+	if isArray {
+		err = p.expect("]]")
+	} else {
+		err = p.expect("]")
+	}
+	if err != nil {
+		return err
+	}
+
+	m, path := p.root, ""
+	for i, key := range keys {
+		path = joinFieldPath(path, key)
+		last := i == len(keys)-1
+		val, exists := m[key]
+
+		if !exists {
+			if last && isArray {
+				val = []interface{}{}
+				p.kinds[path] = tomlArray
+			} else {
+				val = make(map[string]interface{})
+				p.kinds[path] = tomlImplicit
+			}
+
+			m[key] = val
+			p.positions[path] = pos
+		}
+
+		switch kind := p.kinds[path]; {
+		case kind == tomlArray:
+			arr := val.([]interface{})
+			if last {
+				if !isArray {
+					return p.errorf("the key '%s' is already defined as an array of tables", path)
+				}
+
+				// add a new table to the array
+				arr = append(arr, make(map[string]interface{}))
+				m[key] = arr
+			}
+
+			path += "." + strconv.Itoa(len(arr)-1)
+			m = arr[len(arr)-1].(map[string]interface{})
+			if last {
+				p.positions[path] = pos
+				p.kinds[path] = tomlTable
+			}
+		case last && isArray, last && kind != tomlImplicit:
+			return p.errorf("the key '%s' is already defined", path)
+		case kind == tomlValue:
+			return p.errorf("the key '%s' is already defined as a value", path)
+		default:
+			if last {
+				p.kinds[path] = tomlTable
+			}
+			m = val.(map[string]interface{})
+		}
+	}
+
+	p.cur, p.curPath = m, path
+	return nil
+}
+
+// parse the key/value pair to the table m. eg: "a.b = 1"
+func (p *tomlParser) parseKeyValue(m map[string]interface{}, path string) error {
+	p.skipSpace()
+	start := p.off
+	pos := p.text.position(start)
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+
+	if err = p.expect("="); err != nil {
+		return err
+	}
+
+	// the dotted keys create the tables
+	for _, key := range keys[:len(keys)-1] {
+		path = joinFieldPath(path, key)
+		val, exists := m[key]
+		if !exists {
+			val = make(map[string]interface{})
+			m[key] = val
+			p.kinds[path] = tomlDotted
+			p.positions[path] = pos
+		} else if p.kinds[path] != tomlDotted {
+			p.off = start
+			return p.errorf("the key '%s' is already defined", path)
+		}
+		m = val.(map[string]interface{})
+	}
+
+	key := keys[len(keys)-1]
+	path = joinFieldPath(path, key)
+	if _, exists := m[key]; exists {
+		p.off = start
+		return p.errorf("the key '%s' is already defined", path)
+	}
+
+	p.skipSpace()
+	val, err := p.parseValue(path)
+	if err != nil {
+		return err
+	}
+
+	m[key] = val
+	p.kinds[path] = tomlValue
+	p.positions[path] = pos
+	return nil
+}
+
+// parse the value for the field path
+func (p *tomlParser) parseValue(path string) (interface{}, error) {
+	switch c := p.peek(); {
+	case p.hasPrefix(`"""`):
+		return p.parseMultiLineString('"')
+	case c == '"':
+		return p.parseBasicString()
+	case p.hasPrefix("'''"):
+		return p.parseMultiLineString('\'')
+	case c == '\'':
+		return p.parseLiteralString()
+	case c == '[':
+		return p.parseArray(path)
+	case c == '{':
+		return p.parseInlineTable(path)
+	}
+
+	// bool, number, date-time
+	start := p.off
+	for !p.eof() && (isTOMLBareKeyChar(p.src[p.off]) || strings.IndexByte("+.:", p.src[p.off]) >= 0) {
+		p.off++
+		// the space between date and time. eg: "1979-05-27 07:32:00"
+		if p.hasPrefix(" ") && p.off+1 < len(p.src) && isDigit(p.src[p.off+1]) && rxTOMLDate.Match(p.src[start:p.off]) {
+			p.off++
+		}
+	}
+
+	token := string(p.src[start:p.off])
+	switch token {
+	case "":
+		return nil, p.errorf("invalid value")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "inf", "+inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan", "+nan", "-nan":
+		return math.NaN(), nil
+	}
+
+	if val, ok := parseTOMLNumber(token); ok {
+		return val, nil
+	}
+
+	if val, ok := parseTOMLDateTime(token); ok {
+		return val, nil
+	}
+
+	p.off = start
+	return nil, p.errorf("invalid value '%s'", token)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parse the integer to int64, the float to float64
+func parseTOMLNumber(s string) (interface{}, bool) {
+	switch {
+	case rxTOMLInt.MatchString(s):
+		i, err := strconv.ParseInt(strings.Replace(s, "_", "", -1), 10, 64)
+		return i, err == nil
+	case rxTOMLPrefixInt.MatchString(s):
+		base := map[byte]int{'x': 16, 'o': 8, 'b': 2}[s[1]]
+		i, err := strconv.ParseInt(strings.Replace(s[2:], "_", "", -1), base, 64)
+		return i, err == nil
+	case rxTOMLFloat.MatchString(s):
+		f, err := strconv.ParseFloat(strings.Replace(s, "_", "", -1), 64)
+		return f, err == nil
+	}
+	return nil, false
+}
+
+// parse the offset date-time to time.Time. keep the local date-time, date and time as string.
+func parseTOMLDateTime(s string) (interface{}, bool) {
+	// normalize the separator. eg: "1979-05-27 07:32:00Z" -> "1979-05-27T07:32:00Z"
+	norm := strings.ToUpper(s)
+	if len(norm) > 10 && (norm[10] == ' ' || norm[10] == 'T') {
+		norm = norm[:10] + "T" + norm[11:]
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, norm); err == nil {
+		return t, true
+	}
+
+	for _, layout := range []string{"2006-01-02T15:04:05.999999999", "2006-01-02", "15:04:05.999999999"} {
+		if _, err := time.Parse(layout, norm); err == nil {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// parse the single line basic string. eg: "a\tb"
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.off++ // skip '"'
+
+	var sb strings.Builder
+	for {
+		if p.eof() || p.src[p.off] == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+
+		c := p.src[p.off]
+		switch {
+		case c == '"':
+			p.off++
+			return sb.String(), nil
+		case c == '\\':
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+		case c < 0x20 && c != '\t', c == 0x7f:
+			return "", p.errorf("the control character %q must be escaped", c)
+		default:
+			sb.WriteByte(c)
+			p.off++
+		}
+	}
+}
+
+// parse the escape sequence. eg: `\n`, `\u00E9`
+func (p *tomlParser) parseEscape(sb *strings.Builder) error {
+	p.off++ // skip '\'
+	if p.eof() {
+		return p.errorf("unterminated string")
+	}
+
+	c := p.src[p.off]
+	p.off++
+	switch c {
+	case 'b':
+		sb.WriteByte('\b')
+	case 't':
+		sb.WriteByte('\t')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'r':
+		sb.WriteByte('\r')
+	case '"', '\\':
+		sb.WriteByte(c)
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+
+		if p.off+size > len(p.src) {
+			return p.errorf("invalid unicode escape")
+		}
+
+		code, err := strconv.ParseUint(string(p.src[p.off:p.off+size]), 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorf("invalid unicode escape")
+		}
+
+		sb.WriteRune(rune(code))
+		p.off += size
+	default:
+		p.off -= 2
+		return p.errorf("invalid escape sequence '\\%c'", c)
+	}
+	return nil
+}
+
+// parse the single line literal string. eg: 'C:\Users'
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.off++ // skip '\''
+	start := p.off
+	for {
+		if p.eof() || p.src[p.off] == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+
+		if p.src[p.off] == '\'' {
+			s := string(p.src[start:p.off])
+			p.off++
+			return s, nil
+		}
+		p.off++
+	}
+}
+
+// parse the multi-line basic(""") or literal(''') string. the quote is '"' or '\''
+func (p *tomlParser) parseMultiLineString(quote byte) (string, error) {
+	delim := strings.Repeat(string(quote), 3)
+	p.off += 3
+
+	// trim the first newline
+	if p.hasPrefix("\n") {
+		p.off++
+	} else if p.hasPrefix("\r\n") {
+		p.off += 2
+	}
+
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+
+		if p.hasPrefix(delim) {
+			// allow 1 or 2 quotes before the delimiter. eg: `""""a""""`
+			n := 3
+			for n < 5 && p.off+n < len(p.src) && p.src[p.off+n] == quote {
+				n++
+			}
+
+			sb.WriteString(strings.Repeat(string(quote), n-3))
+			p.off += n
+			return sb.String(), nil
+		}
+
+		c := p.src[p.off]
+		if quote == '"' && c == '\\' {
+			// line ending backslash, trim the whitespaces and newlines.
+			end := p.off + 1
+			for end < len(p.src) && (p.src[end] == ' ' || p.src[end] == '\t') {
+				end++
+			}
+
+			if end < len(p.src) && (p.src[end] == '\n' || p.src[end] == '\r') {
+				p.off = end
+				for !p.eof() && strings.IndexByte(" \t\r\n", p.src[p.off]) >= 0 {
+					p.off++
+				}
+				continue
+			}
+
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		if c < 0x20 && c != '\t' && c != '\n' && c != '\r' || c == 0x7f {
+			return "", p.errorf("the control character %q must be escaped", c)
+		}
+
+		sb.WriteByte(c)
+		p.off++
+	}
+}
+
+// parse the array. eg: [1, 2, 3]
+func (p *tomlParser) parseArray(path string) (interface{}, error) {
+	p.off++ // skip '['
+
+	arr := make([]interface{}, 0)
+	for {
+		p.skipBlank()
+		if p.peek() == ']' {
+			p.off++
+			return arr, nil
+		}
+
+		field := path + "." + strconv.Itoa(len(arr))
+		pos := p.text.position(p.off)
+		val, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+
+		arr = append(arr, val)
+		p.positions[field] = pos
+		p.kinds[field] = tomlValue
+
+		p.skipBlank()
+		switch p.peek() {
+		case ',':
+			p.off++
+		case ']':
+			p.off++
+			return arr, nil
+		default:
+			return nil, p.errorf("expected ',' or ']' in the array")
+		}
+	}
+}
+
+// parse the inline table. eg: {x = 1, y = 2}
+func (p *tomlParser) parseInlineTable(path string) (interface{}, error) {
+	p.off++ // skip '{'
+
+	tbl := make(map[string]interface{})
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.off++
+		return tbl, nil
+	}
+
+	for {
+		if err := p.parseKeyValue(tbl, path); err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.off++
+		case '}':
+			p.off++
+			return tbl, nil
+		default:
+			return nil, p.errorf("expected ',' or '}' in the inline table")
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package validate
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOMLParser_values(t *testing.T) {
+	is := assert.New(t)
+
+	p := newTOMLParser([]byte(`
+str1 = "a\tb \"c\" \u00E9"
+str2 = 'C:\Users\name'
+str3 = """
+one \
+  two"""
+str4 = '''
+line1
+line2'''
+str5 = """say ""hi"""""
+int1 = +99
+int2 = 1_000
+int3 = 0xDEAD_beef
+int4 = 0o755
+int5 = 0b1101
+flt1 = -3.14
+flt2 = 5e+22
+flt3 = inf
+flt4 = nan
+bool = false
+odt1 = 1979-05-27T07:32:00Z
+odt2 = 1979-05-27 00:32:00.999-07:00
+ldt = 1979-05-27T07:32:00
+ld = 1979-05-27
+lt = 07:32:00
+arr = [ 1, 2, # the comment
+  3, ]
+nested = [[1, 2], ["a"], []]
+point = { x = 1, y.z = "a" }
+"quoted key" = 1
+`))
+	is.NoError(p.parse())
+
+	m := p.root
+	is.Equal("a\tb \"c\" é", m["str1"])
+	is.Equal(`C:\Users\name`, m["str2"])
+	is.Equal("one two", m["str3"])
+	is.Equal("line1\nline2", m["str4"])
+	is.Equal(`say ""hi""`, m["str5"])
+	is.Equal(int64(99), m["int1"])
+	is.Equal(int64(1000), m["int2"])
+	is.Equal(int64(0xDEADBEEF), m["int3"])
+	is.Equal(int64(0755), m["int4"])
+	is.Equal(int64(13), m["int5"])
+	is.Equal(-3.14, m["flt1"])
+	is.Equal(5e+22, m["flt2"])
+	is.True(math.IsInf(m["flt3"].(float64), 1))
+	is.True(math.IsNaN(m["flt4"].(float64)))
+	is.Equal(false, m["bool"])
+	is.Equal(time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC), m["odt1"].(time.Time).UTC())
+	is.Equal(time.Date(1979, 5, 27, 7, 32, 0, 999000000, time.UTC), m["odt2"].(time.Time).UTC())
+	is.Equal("1979-05-27T07:32:00", m["ldt"])
+	is.Equal("1979-05-27", m["ld"])
+	is.Equal("07:32:00", m["lt"])
+	is.Equal([]interface{}{int64(1), int64(2), int64(3)}, m["arr"])
+	is.Equal([]interface{}{[]interface{}{int64(1), int64(2)}, []interface{}{"a"}, []interface{}{}}, m["nested"])
+	is.Equal(map[string]interface{}{"x": int64(1), "y": map[string]interface{}{"z": "a"}}, m["point"])
+	is.Equal(int64(1), m["quoted key"])
+
+	// positions
+	is.Equal(27, p.positions["arr.2"].Line)
+	is.Equal(3, p.positions["arr.2"].Column)
+	is.Equal(29, p.positions["point.y.z"].Line)
+	is.Equal(18, p.positions["point.y.z"].Column)
+}
+
+func TestTOMLParser_tables(t *testing.T) {
+	is := assert.New(t)
+
+	p := newTOMLParser([]byte(`
+[a.b]
+c = 1
+[a]
+d = 2
+[[a.list]]
+e = 3
+[a.list.sub]
+f = 4
+[[a.list]]
+e = 5
+[x]
+y.z = 1
+[x.y.w]
+v = 2
+`))
+	is.NoError(p.parse())
+
+	val, _ := getByPath("a.b.c", p.root)
+	is.Equal(int64(1), val)
+	val, _ = getByPath("a.d", p.root)
+	is.Equal(int64(2), val)
+	val, _ = getByPath("a.list.0.sub.f", p.root)
+	is.Equal(int64(4), val)
+	val, _ = getByPath("a.list.1.e", p.root)
+	is.Equal(int64(5), val)
+	val, _ = getByPath("x.y.w.v", p.root)
+	is.Equal(int64(2), val)
+
+	is.Equal(10, p.positions["a.list.1"].Line)
+	is.Equal(11, p.positions["a.list.1.e"].Line)
+}
+
+func TestTOMLParser_errors(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []string{
+		"a = 1\na = 2",
+		"a = 1 b = 2",
+		"a",
+		"a = ",
+		"a = 01",
+		"a = 1__0",
+		"a = 0x",
+		"a = 99999999999999999999",
+		"a = \"abc",
+		"a = 'abc\n'",
+		"a = \"\\q\"",
+		"a = \"\\u00\"",
+		"a = \"\"\"abc",
+		"a = [1 2]",
+		"a = {x = 1\n}",
+		"a = {x = 1, x = 2}",
+		"a = 1979-13-45",
+		"[a]\n[a]",
+		"[a\n",
+		"[[a]]\n[a]",
+		"a = 1\n[a]",
+		"a = {x = 1}\n[a.y]",
+		"[a]\nb.c = 1\n[a.b]",
+		"[a.b]\n[a]\nb.c = 1",
+		"\"a.b\" = 1",
+		"a = \"\x01\"",
+		"a = \"\xff\"",
+	}
+
+	for _, tt := range tests {
+		err := newTOMLParser([]byte(tt)).parse()
+		is.Error(err, tt)
+	}
+
+	err := newTOMLParser([]byte("a = 1\nb = 2\nb = 3")).parse()
+	is.EqualError(err, "toml: line 3, column 1: the key 'b' is already defined")
+
+	err = newTOMLParser([]byte("a = 1\n\"a.b\" = 2")).parse()
+	is.EqualError(err, "toml: line 2, column 6: the key 'a.b' cannot be used as the field path")
+}
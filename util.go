@@ -1,12 +1,17 @@
 package validate
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/gookit/filter"
@@ -130,6 +135,8 @@ func valueToInt64(v interface{}, strict bool) (i64 int64, err error) {
 			return 0, errConvertFail
 		}
 		i64, err = strconv.ParseInt(filter.Trim(tVal), 10, 0)
+	case json.Number: // always is a number
+		i64, err = tVal.Int64()
 	case int:
 		i64 = int64(tVal)
 	case int8:
@@ -182,43 +189,274 @@ func CalcLength(val interface{}) int {
 	return ValueLen(reflect.ValueOf(val))
 }
 
-// value compare. use for compare int, string.
-func valueCompare(srcVal, dstVal interface{}, op string) (ok bool) {
-	var err error
-	var srcInt, dstInt int64
+// valueCompare compare the srcVal and dstVal by the op. returns false on the values cannot be compared.
+// the plain strings are compared by length, by lexical on the lexical is true.
+func valueCompare(srcVal, dstVal interface{}, op string, lexical bool) (ok bool) {
+	ret, err := compareValues(srcVal, dstVal, lexical)
+	if err != nil {
+		return false
+	}
 
-	// string: compare length
-	if str, ok := srcVal.(string); ok {
-		dst, ok := dstVal.(string)
-		if !ok {
-			return false
+	switch op {
+	case "lt":
+		ok = ret < 0
+	case "lte":
+		ok = ret <= 0
+	case "gt":
+		ok = ret > 0
+	case "gte":
+		ok = ret >= 0
+	}
+	return
+}
+
+// compareValues compare two values, returns -1, 0, 1 on the srcVal is less, equal, greater than the dstVal.
+//
+// support:
+// 	- time.Time: compare with time.Time or date string.
+// 	- int, uint, float: compare by the number value, float is used on any of them is float.
+// 	- string: compare by number on both are numeric, by time on both are date,
+// 	  otherwise compare by length, or by lexical on the lexical is true.
+//
+// returns error on the values types cannot be compared. eg: compare time.Time with int
+func compareValues(srcVal, dstVal interface{}, lexical bool) (int, error) {
+	src, dst := indirectValue(srcVal), indirectValue(dstVal)
+	if src == nil || dst == nil {
+		return 0, fmt.Errorf("cannot compare %T with %T", srcVal, dstVal)
+	}
+
+	// compare time
+	st, srcIsTime := src.(time.Time)
+	dt, dstIsTime := dst.(time.Time)
+	if srcIsTime || dstIsTime {
+		var err error
+		if !srcIsTime {
+			st, err = toTime(src)
+		} else if !dstIsTime {
+			dt, err = toTime(dst)
 		}
 
-		srcInt = int64(len(str))
-		dstInt = int64(len(dst))
-	} else { // as int: compare size
-		srcInt, err = filter.Int64(srcVal)
 		if err != nil {
-			return false
+			return 0, fmt.Errorf("cannot compare %T with %T", srcVal, dstVal)
+		}
+		return compareTime(st, dt), nil
+	}
+
+	srcStr, srcIsStr := src.(string)
+	dstStr, dstIsStr := dst.(string)
+	if srcIsStr && dstIsStr {
+		// both are numeric string
+		if sr, ok := toRat(srcStr); ok {
+			if dr, ok := toRat(dstStr); ok {
+				return sr.Cmp(dr), nil
+			}
+		}
+
+		// both are date string
+		if st, err := strutil.ToTime(srcStr); err == nil {
+			if dt, err := strutil.ToTime(dstStr); err == nil {
+				return compareTime(st, dt), nil
+			}
+		}
+
+		if lexical {
+			return strings.Compare(srcStr, dstStr), nil
+		}
+
+		// compare by length, it is the default behavior of the old versions.
+		switch {
+		case len(srcStr) < len(dstStr):
+			return -1, nil
+		case len(srcStr) > len(dstStr):
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	// compare number. NOTICE: the string will be parsed as number
+	ret, ok := compareNumber(src, dst)
+	if !ok {
+		return 0, fmt.Errorf("cannot compare %T with %T", srcVal, dstVal)
+	}
+	return ret, nil
+}
+
+// compareNumber compare two number values, compare by int64 on both are integer, otherwise by float64.
+// so the float value will not be truncated. returns false on any of them is not a number.
+func compareNumber(srcVal, dstVal interface{}) (int, bool) {
+	si, _, sIsInt, sErr := toNumber(srcVal)
+	di, _, dIsInt, dErr := toNumber(dstVal)
+	if sErr == nil && dErr == nil && sIsInt && dIsInt {
+		switch {
+		case si < di:
+			return -1, true
+		case si > di:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	// compare by big.Rat, keep the precision of big number and decimal string
+	sr, ok := toRat(srcVal)
+	if !ok {
+		return 0, false
+	}
+
+	dr, ok := toRat(dstVal)
+	if !ok {
+		return 0, false
+	}
+	return sr.Cmp(dr), true
+}
+
+// plain decimal number string. eg: "12", "-0.5", "12345678901234567890.12"
+var rxDecimalNumber = regexp.MustCompile(`^[-+]?(\d+(\.\d*)?|\.\d+)$`)
+
+// toRat convert the number value to *big.Rat without losing precision.
+// support int(X), uint(X), float(X), numeric string, json.Number, big.Int, big.Float and big.Rat.
+// the nil value is not a number.
+func toRat(val interface{}) (*big.Rat, bool) {
+	switch tv := val.(type) {
+	case nil:
+		return nil, false
+	case *big.Int:
+		if tv == nil {
+			return nil, false
+		}
+		return new(big.Rat).SetInt(tv), true
+	case big.Int:
+		return new(big.Rat).SetInt(&tv), true
+	case *big.Float:
+		if tv == nil || tv.IsInf() {
+			return nil, false
+		}
+		r, _ := tv.Rat(nil)
+		return r, true
+	case big.Float:
+		return toRat(&tv)
+	case *big.Rat:
+		if tv == nil {
+			return nil, false
+		}
+		return new(big.Rat).Set(tv), true
+	case big.Rat:
+		return new(big.Rat).Set(&tv), true
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(rv.Uint())), true
+	case reflect.Float32, reflect.Float64:
+		// use the shortest decimal repr. eg: float32(0.1) is "0.1", not 0.100000001490116...
+		bitSize := 64
+		if rv.Kind() == reflect.Float32 {
+			bitSize = 32
+		}
+		return floatToRat(rv.Float(), bitSize)
+	case reflect.String: // string, json.Number
+		str := strings.TrimSpace(rv.String())
+		if rxDecimalNumber.MatchString(str) {
+			return new(big.Rat).SetString(str)
 		}
 
-		dstInt, err = filter.Int64(dstVal)
+		// with exponent. eg: "1e5". parse by float for limit the size.
+		f64, err := strconv.ParseFloat(str, 64)
 		if err != nil {
-			return false
+			return nil, false
 		}
+		return floatToRat(f64, 64)
 	}
+	return nil, false
+}
 
-	switch op {
-	case "lt":
-		ok = srcInt < dstInt
-	case "lte":
-		ok = srcInt <= dstInt
-	case "gt":
-		ok = srcInt > dstInt
-	case "gte":
-		ok = srcInt >= dstInt
+func floatToRat(f64 float64, bitSize int) (*big.Rat, bool) {
+	if math.IsNaN(f64) || math.IsInf(f64, 0) {
+		return nil, false
 	}
-	return
+	return new(big.Rat).SetString(strconv.FormatFloat(f64, 'g', -1, bitSize))
+}
+
+// toNumber convert the int, uint, float, numeric string or json.Number value to number.
+// isInt is true on the value is an integer, the i64 is valid.
+// the nil value is not a number.
+func toNumber(val interface{}) (i64 int64, f64 float64, isInt bool, err error) {
+	if val == nil {
+		return 0, 0, false, errConvertFail
+	}
+
+	if str, ok := val.(string); ok {
+		str = strings.TrimSpace(str)
+		if i64, err = strconv.ParseInt(str, 10, 64); err == nil {
+			return i64, float64(i64), true, nil
+		}
+
+		f64, err = strconv.ParseFloat(str, 64)
+		return
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i64 = rv.Int()
+		return i64, float64(i64), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u64 := rv.Uint()
+		if u64 > math.MaxInt64 {
+			return 0, float64(u64), false, nil
+		}
+		return int64(u64), float64(u64), true, nil
+	case reflect.Float32, reflect.Float64:
+		return 0, rv.Float(), false, nil
+	case reflect.String: // eg: json.Number
+		return toNumber(rv.String())
+	}
+	return 0, 0, false, errConvertFail
+}
+
+// indirectValue returns the value that the pointer points to. returns nil for nil pointer.
+func indirectValue(val interface{}) interface{} {
+	rv := reflect.ValueOf(val)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
+
+// isTimeValue check the value is time.Time or *time.Time
+func isTimeValue(val interface{}) bool {
+	_, ok := indirectValue(val).(time.Time)
+	return ok
+}
+
+// toTime convert the time.Time or date string to time.Time
+func toTime(val interface{}) (time.Time, error) {
+	switch tv := val.(type) {
+	case time.Time:
+		return tv, nil
+	case string:
+		return strutil.ToTime(tv)
+	}
+	return time.Time{}, errConvertFail
+}
+
+func compareTime(st, dt time.Time) int {
+	switch {
+	case st.Before(dt):
+		return -1
+	case st.After(dt):
+		return 1
+	}
+	return 0
 }
 
 // func nameOfFunc(fv reflect.Value) string {
@@ -294,6 +532,14 @@ func convertType(srcVal interface{}, srcKind kind, dstType reflect.Kind) (interf
 			return mathutil.Int(srcVal)
 		case reflect.Int64:
 			return mathutil.Int64(srcVal)
+		case reflect.Float64:
+			if f, err := strconv.ParseFloat(reflect.ValueOf(srcVal).String(), 64); err == nil {
+				return f, nil
+			}
+		case reflect.Bool:
+			if b, err := strconv.ParseBool(reflect.ValueOf(srcVal).String()); err == nil {
+				return b, nil
+			}
 		}
 	case intKind, uintKind:
 		i64 := filter.MustInt64(srcVal)
@@ -602,6 +848,132 @@ func IsZero(v reflect.Value) bool {
 	}
 }
 
+/*************************************************************
+ * Field path:
+ *  - get value by path. eg: "items.0.price"
+ *  - list element keys for expand wildcard path. eg: "items.*.price"
+ *************************************************************/
+
+// elemKeysLister the data source can list the element keys of the field path.
+// will use for expand the wildcard path. eg: "items.*.price"
+type elemKeysLister interface {
+	elemKeys(field string) []string
+}
+
+// getByPath get value by path from the map. support slice index and map key.
+// eg: "top.sub" "items.0.price"
+func getByPath(path string, mp map[string]interface{}) (interface{}, bool) {
+	if val, ok := mp[path]; ok {
+		return val, true
+	}
+
+	// has sub key? eg. "top.sub"
+	if !strings.ContainsRune(path, '.') {
+		return nil, false
+	}
+
+	nodes := strings.Split(path, ".")
+	item, ok := mp[nodes[0]]
+	if !ok {
+		return nil, false
+	}
+
+	for _, node := range nodes[1:] {
+		if item, ok = elemByKey(item, node); !ok {
+			return nil, false
+		}
+	}
+	return item, true
+}
+
+// elemByKey get element value of the map, slice, array by the key string.
+func elemByKey(val interface{}, key string) (interface{}, bool) {
+	switch tVal := val.(type) {
+	case map[string]interface{}: // decode from JSON
+		elem, ok := tVal[key]
+		return elem, ok
+	case map[string]string:
+		elem, ok := tVal[key]
+		return elem, ok
+	case []interface{}: // decode from JSON
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(tVal) {
+			return nil, false
+		}
+		return tVal[index], true
+	}
+
+	rv := reflect.ValueOf(val)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, false
+	}
+
+	ev := indirectInterface(elemValueByKey(removeValuePtr(rv), key))
+	if !ev.IsValid() || !ev.CanInterface() {
+		return nil, false
+	}
+	return ev.Interface(), true
+}
+
+// elemValueByKey get element reflect value of the map, slice, array by the key string.
+// will return an invalid reflect.Value on not found.
+func elemValueByKey(rv reflect.Value, key string) reflect.Value {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Slice:
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= rv.Len() {
+			return emptyValue
+		}
+		return rv.Index(index)
+	case reflect.Map:
+		kt := rv.Type().Key()
+		// fast path: map key is string kind.
+		if kt.Kind() == reflect.String {
+			return rv.MapIndex(reflect.ValueOf(key).Convert(kt))
+		}
+
+		// other kind key, compare with the formatted key string.
+		for _, mk := range rv.MapKeys() {
+			if fmt.Sprint(mk.Interface()) == key {
+				return rv.MapIndex(mk)
+			}
+		}
+	}
+	return emptyValue
+}
+
+// isContainerKind check the kind is struct, map, slice or array.
+func isContainerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// valueElemKeys get the element keys of the slice, array or map value. the map keys will be sorted.
+func valueElemKeys(val interface{}) (keys []string) {
+	rv := reflect.ValueOf(val)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return
+	}
+
+	rv = removeValuePtr(rv)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Slice:
+		keys = make([]string, rv.Len())
+		for i := range keys {
+			keys[i] = strconv.Itoa(i)
+		}
+	case reflect.Map:
+		for _, mk := range rv.MapKeys() {
+			keys = append(keys, fmt.Sprint(mk.Interface()))
+		}
+		sort.Strings(keys)
+	}
+	return
+}
+
 // Remove type multiple pointer
 func removeTypePtr(t reflect.Type) reflect.Type {
 	for t.Kind() == reflect.Ptr {
@@ -617,3 +989,51 @@ func removeValuePtr(t reflect.Value) reflect.Value {
 	}
 	return t
 }
+
+// errorIs is same as the errors.Is() of the go 1.13+, walk the chain of the err by the Unwrap() method.
+func errorIs(err, target error) bool {
+	if err == nil || target == nil {
+		return err == target
+	}
+
+	comparable := reflect.TypeOf(target).Comparable()
+	for err != nil {
+		if comparable && err == target {
+			return true
+		}
+		if x, ok := err.(interface{ Is(error) bool }); ok && x.Is(target) {
+			return true
+		}
+		err = unwrapError(err)
+	}
+	return false
+}
+
+// errorAs is same as the errors.As() of the go 1.13+, the target must be a non-nil pointer.
+func errorAs(err error, target interface{}) bool {
+	rv := reflect.ValueOf(target)
+	if target == nil || rv.Kind() != reflect.Ptr || rv.IsNil() {
+		panic("validate: the target must be a non-nil pointer")
+	}
+
+	typ := rv.Type().Elem()
+	for err != nil {
+		if reflect.TypeOf(err).AssignableTo(typ) {
+			rv.Elem().Set(reflect.ValueOf(err))
+			return true
+		}
+		if x, ok := err.(interface{ As(interface{}) bool }); ok && x.As(target) {
+			return true
+		}
+		err = unwrapError(err)
+	}
+	return false
+}
+
+// get the wrapped error by the Unwrap() method. returns nil on it is not exists.
+func unwrapError(err error) error {
+	if x, ok := err.(interface{ Unwrap() error }); ok {
+		return x.Unwrap()
+	}
+	return nil
+}
@@ -7,13 +7,18 @@
 package validate
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 )
 
 // M is short name for map[string]interface{}
@@ -25,28 +30,39 @@ type MS map[string]string
 // SValues simple values
 type SValues map[string][]string
 
-// One get one item's value string
+// One get one item's value string. the item is first one on sorted by key.
 func (ms MS) One() string {
-	for _, msg := range ms {
-		return msg
+	if len(ms) == 0 {
+		return ""
 	}
-	return ""
+	return ms[ms.sortedKeys()[0]]
 }
 
-// String convert map[string]string to string
+// String convert map[string]string to string, the items are sorted by key.
 func (ms MS) String() string {
 	if len(ms) == 0 {
 		return ""
 	}
 
-	var ss []string
-	for name, msg := range ms {
-		ss = append(ss, " "+name+": "+msg)
+	ss := make([]string, 0, len(ms))
+	for _, name := range ms.sortedKeys() {
+		ss = append(ss, " "+name+": "+ms[name])
 	}
 
 	return strings.Join(ss, "\n")
 }
 
+// get the sorted keys, use for keep the order is deterministic.
+func (ms MS) sortedKeys() []string {
+	keys := make([]string, 0, len(ms))
+	for key := range ms {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
 // GlobalOption settings for validate
 type GlobalOption struct {
 	// FilterTag name in the struct tags.
@@ -57,6 +73,8 @@ type GlobalOption struct {
 	FieldTag string
 	// MessageTag define error message for the field.
 	MessageTag string
+	// KeyTag define the rules for validate the map keys. default: validateKey
+	KeyTag string
 	// StopOnError If true: An error occurs, it will cease to continue to verify
 	StopOnError bool
 	// SkipOnEmpty Skip check on field not exist or value is empty
@@ -67,23 +85,38 @@ type GlobalOption struct {
 	CheckDefault bool
 	// CheckZero Whether validate the default zero value. (intX,uintX: 0, string: "")
 	CheckZero bool
+	// LexicalCompare Whether compare the plain strings by lexical in the field compare validators.
+	// default is compare by the length. eg: "gtField:name"
+	LexicalCompare bool
+	// UseNumber Whether decode the JSON number as json.Number instead of float64.
+	// available for FromJSON(), FromJSONBytes() and JSON request body.
+	UseNumber bool
 }
 
 // global options
-var gOpt = newGlobalOption()
+var (
+	gOpt  = newGlobalOption()
+	optMu sync.RWMutex
+)
 
 // Config global options
 func Config(fn func(opt *GlobalOption)) {
+	optMu.Lock()
 	fn(gOpt)
+	optMu.Unlock()
 }
 
 // ResetOption reset global option
 func ResetOption() {
+	optMu.Lock()
 	gOpt = newGlobalOption()
+	optMu.Unlock()
 }
 
 // Option get global options
 func Option() GlobalOption {
+	optMu.RLock()
+	defer optMu.RUnlock()
 	return *gOpt
 }
 
@@ -98,16 +131,23 @@ func newGlobalOption() *GlobalOption {
 		MessageTag: messageTag,
 		// tag name in struct tags
 		ValidateTag: validateTag,
+		KeyTag:      keyTag,
 	}
 }
 
 func newValidation(data DataFace) *Validation {
+	return newValidationWith(defRegistry, data)
+}
+
+func newValidationWith(reg *Registry, data DataFace) *Validation {
+	opt := Option()
 	v := &Validation{
+		reg:    reg,
 		Errors: make(Errors),
 		// add data source on usage
 		data: data,
 		// create message translator
-		trans: NewTranslator(),
+		trans: newTranslator(reg),
 		// validated data
 		safeData: make(map[string]interface{}),
 		// validator names
@@ -115,13 +155,16 @@ func newValidation(data DataFace) *Validation {
 		// filtered data
 		filteredData: make(map[string]interface{}),
 		// default config
-		StopOnError: gOpt.StopOnError,
-		SkipOnEmpty: gOpt.SkipOnEmpty,
+		StopOnError: opt.StopOnError,
+		SkipOnEmpty: opt.SkipOnEmpty,
+		// field compare
+		LexicalCompare: opt.LexicalCompare,
 	}
 
 	// init build in context validator
 	v.validatorValues = map[string]reflect.Value{
 		"required":           reflect.ValueOf(v.Required),
+		"requiredKey":        reflect.ValueOf(v.RequiredKey),
 		"requiredIf":         reflect.ValueOf(v.RequiredIf),
 		"requiredUnless":     reflect.ValueOf(v.RequiredUnless),
 		"requiredWith":       reflect.ValueOf(v.RequiredWith),
@@ -135,6 +178,10 @@ func newValidation(data DataFace) *Validation {
 		"gteField": reflect.ValueOf(v.GteField),
 		"ltField":  reflect.ValueOf(v.LtField),
 		"lteField": reflect.ValueOf(v.LteField),
+		// time compare
+		"after":      reflect.ValueOf(v.After),
+		"before":     reflect.ValueOf(v.Before),
+		"withinLast": reflect.ValueOf(v.WithinLast),
 		// file upload check
 		"isFile":      reflect.ValueOf(v.IsFormFile),
 		"isImage":     reflect.ValueOf(v.IsFormImage),
@@ -246,9 +293,10 @@ func FromJSON(s string) (*MapData, error) {
 }
 
 // FromJSONBytes string build data instance.
+// if the Option().UseNumber is true, the number will be decoded as json.Number.
 func FromJSONBytes(bs []byte) (*MapData, error) {
 	mp := map[string]interface{}{}
-	if err := json.Unmarshal(bs, &mp); err != nil {
+	if err := unmarshalJSON(bs, &mp, Option().UseNumber); err != nil {
 		return nil, err
 	}
 
@@ -262,10 +310,29 @@ func FromJSONBytes(bs []byte) (*MapData, error) {
 	return data, nil
 }
 
+// unmarshalJSON like json.Unmarshal, but can decode the number as json.Number
+func unmarshalJSON(bs []byte, ptr interface{}, useNumber bool) error {
+	if !useNumber {
+		return json.Unmarshal(bs, ptr)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.UseNumber()
+	if err := dec.Decode(ptr); err != nil {
+		return err
+	}
+
+	// should not have more data after the JSON value
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("validate: invalid data after top-level JSON value")
+	}
+	return nil
+}
+
 // FromStruct create a Data from struct
 func FromStruct(s interface{}) (*StructData, error) {
 	data := &StructData{
-		ValidateTag: gOpt.ValidateTag,
+		ValidateTag: Option().ValidateTag,
 		// init map
 		fieldNames:  make(map[string]int8),
 		fieldValues: make(map[string]reflect.Value),
@@ -1,8 +1,10 @@
 package validate
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -68,6 +70,42 @@ func TestUtil_Func_goodName(t *testing.T) {
 	}
 }
 
+func TestUtil_Func_errorIs(t *testing.T) {
+	is := assert.New(t)
+	fe := &FieldError{Field: "name", Err: ErrEmptyData}
+
+	is.True(errorIs(fe, fe))
+	is.True(errorIs(fe, ErrEmptyData))
+	is.True(errorIs(FieldErrors{fe}, ErrEmptyData))
+	is.False(errorIs(fe, ErrNoField))
+	is.False(errorIs(nil, ErrNoField))
+	is.True(errorIs(nil, nil))
+	// the FieldErrors is not comparable
+	is.False(errorIs(ErrEmptyData, FieldErrors{fe}))
+}
+
+func TestUtil_Func_errorAs(t *testing.T) {
+	is := assert.New(t)
+	pe := &RuleParseError{Field: "name", Message: "invalid rule"}
+	fes := FieldErrors{{Field: "name", Err: pe}}
+
+	var fe *FieldError
+	is.True(errorAs(fes, &fe))
+	is.Equal("name", fe.Field)
+
+	var target *RuleParseError
+	is.True(errorAs(fes, &target))
+	is.Equal(pe, target)
+	is.True(errorAs(fe, &target))
+
+	var list FieldErrors
+	is.False(errorAs(fe, &list))
+	is.False(errorAs(nil, &list))
+	is.Panics(func() {
+		errorAs(fe, list)
+	})
+}
+
 func Test_Util_Func_convertType(t *testing.T) {
 	nVal, err := convertType(23, intKind, reflect.String)
 	assert.NoError(t, err)
@@ -76,6 +114,18 @@ func Test_Util_Func_convertType(t *testing.T) {
 	nVal, err = convertType(uint(23), uintKind, reflect.String)
 	assert.NoError(t, err)
 	assert.Equal(t, "23", nVal)
+
+	nVal, err = convertType("2.5", stringKind, reflect.Float64)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, nVal)
+
+	nVal, err = convertType("true", stringKind, reflect.Bool)
+	assert.NoError(t, err)
+	assert.Equal(t, true, nVal)
+
+	nVal, err = convertType("abc", stringKind, reflect.Float64)
+	assert.Error(t, err)
+	assert.Nil(t, nVal)
 }
 
 func Test_IsZero(t *testing.T) {
@@ -142,3 +192,19 @@ func Test_Struct_nilPtr_field2(t *testing.T) {
 	assert.Contains(t, v.Errors.FieldOne("Sex"), "sex is required")
 	fmt.Println(v.Errors)
 }
+
+// ------------------ test helpers ------------------
+
+func jsonString(val interface{}) string {
+	bs, err := json.Marshal(val)
+	if err != nil {
+		panic(err)
+	}
+	return string(bs)
+}
+
+func sortedFields(fes FieldErrors) []string {
+	fields := fes.Fields()
+	sort.Strings(fields)
+	return fields
+}
@@ -1,7 +1,9 @@
 package validate
 
 import (
+	"context"
 	"reflect"
+	"strings"
 )
 
 // const requiredValidator = "required"
@@ -24,6 +26,14 @@ func (v *Validation) ValidateData(data DataFace) bool {
 	return v.Validate()
 }
 
+// ValidateContext processing with the context. the context will be passed to
+// the validators which first param is context.Context.
+// will stop validate and add an error on the context is done.
+func (v *Validation) ValidateContext(ctx context.Context, scene ...string) bool {
+	v.ctx = ctx
+	return v.Validate(scene...)
+}
+
 // Validate processing
 func (v *Validation) Validate(scene ...string) bool {
 	// has been validated OR has error
@@ -35,18 +45,37 @@ func (v *Validation) Validate(scene ...string) bool {
 	v.SetScene(scene...)
 	v.sceneFields = v.sceneFieldMap()
 
+	// the data source prepare by the rules. eg: EnvData
+	if dp, ok := v.data.(dataPreparer); ok {
+		dp.prepare(v)
+	}
+
 	// apply filter rules before validate.
 	if false == v.Filtering() && v.StopOnError {
 		return false
 	}
 
+	// apply rules concurrently
+	if v.Concurrency > 1 {
+		v.validateConcurrently()
+		return v.finishValidate()
+	}
+
 	// apply rule to validate data.
 	for _, rule := range v.rules {
-		if rule.Apply(v) {
+		// the context is canceled or deadline exceeded.
+		if v.ctxDone() || rule.Apply(v) {
 			break
 		}
 	}
 
+	if v.aborted {
+		v.addContextError(v.ctx.Err())
+	}
+	return v.finishValidate()
+}
+
+func (v *Validation) finishValidate() bool {
 	v.hasValidated = true
 	if v.hasError {
 		// clear safe data on error.
@@ -68,102 +97,267 @@ func (r *Rule) Apply(v *Validation) (stop bool) {
 		return
 	}
 
+	// validate each field
+	for _, field := range r.fields {
+		if r.applyOne(v, field) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// apply the rule for one of the rule fields.
+func (r *Rule) applyOne(v *Validation, field string) (stop bool) {
+	if v.isNotNeedToCheck(field) {
+		return false
+	}
+
+	// map key rules. eg: "labels.#key"
+	if strings.HasSuffix(field, keyPathSuffix) {
+		return r.applyKeys(v, field)
+	}
+
+	// wildcard field, expand it to real paths. eg: "items.*.price" -> "items.0.price"
+	if strings.ContainsRune(field, '*') {
+		for _, path := range v.expandField(field) {
+			// check the context for each path, the expanded paths maybe very many.
+			if v.ctxDone() || r.applyField(v, path, field) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return r.applyField(v, field, field)
+}
+
+// apply the rule for one field.
+// pattern is the field name in the rule, it is different from the field on it contains wildcard.
+func (r *Rule) applyField(v *Validation, field, pattern string) (stop bool) {
+	// the condition of the field path is false
+	if r.fieldCond != nil && !r.fieldCond(v, field) {
+		return false
+	}
+
 	var err error
 	// get real validator name
 	name := r.realName
 	// validator name is not "required"
 	isNotRequired := r.nameNotRequired
 
-	// validate each field
-	for _, field := range r.fields {
-		if v.isNotNeedToCheck(field) {
-			continue
+	// uploaded file validate
+	if isFileValidator(name) {
+		status := r.fileValidate(field, name, v)
+		if status == statusFail {
+			// build and collect error message
+			val, _ := v.Raw(field)
+			r.addError(v, field, pattern, val, r.arguments)
+			return v.StopOnError
 		}
+		return false
+	}
 
-		// uploaded file validate
-		if isFileValidator(name) {
-			status := r.fileValidate(field, name, v)
-			if status == statusFail {
-				// build and collect error message
-				v.AddError(field, r.validator, r.errorMessage(field, r.validator, v))
-				if v.StopOnError {
-					return true
-				}
-			}
-			continue
+	// the default value has been saved by the previous rule, dont need check it
+	if !v.CheckDefault && v.isDefaultSaved(field, pattern) {
+		return false
+	}
+
+	// get field value. val, exist := v.Get(field)
+	val, exist, isDefault := v.getWithDefault(field, pattern)
+
+	// not exists but has default value
+	if isDefault {
+		// update source data field value and re-set value
+		val, err := v.updateValue(field, val)
+		if err != nil {
+			// panicf(err.Error())
+			v.AddErrorf(field, err.Error())
+			return v.StopOnError
 		}
 
-		// get field value. val, exist := v.Get(field)
-		val, exist, isDefault := v.GetWithDefault(field)
+		// dont need check default value
+		if !v.CheckDefault {
+			// save validated value.
+			v.safeData[field] = val
+			return false
+		}
 
-		// not exists but has default value
-		if isDefault {
-			// update source data field value and re-set value
-			val, err := v.updateValue(field, val)
-			if err != nil {
-				// panicf(err.Error())
-				v.AddErrorf(field, err.Error())
-				if v.StopOnError {
-					return true
-				}
-				continue
-			}
+		// go on check custom default value
+		exist = true
+	} else if r.optional { // r.optional=true. skip check.
+		return false
+	}
 
-			// dont need check default value
-			if !v.CheckDefault {
-				// save validated value.
-				v.safeData[field] = val
-				continue
-			}
+	// apply filter func.
+	if exist && r.filterFunc != nil {
+		if val, err = r.filterFunc(val); err != nil { // has error
+			v.AddError(filterError, filterError, err.Error())
+			return true
+		}
+
+		// update source field value
+		newVal, err := v.updateValue(field, val)
+		if err != nil {
+			// panicf(err.Error())
+			v.AddErrorf(field, err.Error())
+			return v.StopOnError
+		}
+
+		// re-set value
+		val = newVal
+		// save filtered value.
+		v.filteredData[field] = val
+	}
+
+	// field not exists AND skip on missing. the null value is only checked by the type, like the JSON Schema
+	if r.skipMissing && isNotRequired && (!exist || val == nil && name != "isJSONType") {
+		return false
+	}
+
+	// empty value AND skip on empty.
+	if r.skipEmpty && isNotRequired && IsEmpty(val) {
+		return false
+	}
 
-			// go on check custom default value
-			exist = true
-		} else if r.optional { // r.optional=true. skip check.
+	// validate the rule group
+	if r.anyOf != nil {
+		if r.anyOfValidate(field, pattern, val, v) {
+			v.safeData[field] = val
+		}
+		return v.shouldStop()
+	}
+
+	// validate field value
+	if ok, args := r.valueValidate(field, name, val, v); ok {
+		v.safeData[field] = val // save validated value.
+	} else { // build and collect error message
+		r.addError(v, field, pattern, val, args)
+	}
+
+	// stop on error
+	return v.shouldStop()
+}
+
+// apply the rule for each key of the map field. the pattern like "labels.#key", "items.*.#key"
+// error will be reported by the key path. eg: "labels.some-key"
+func (r *Rule) applyKeys(v *Validation, pattern string) (stop bool) {
+	mapField := strings.TrimSuffix(pattern, keyPathSuffix)
+
+	mapPaths := []string{mapField}
+	if strings.ContainsRune(mapField, '*') {
+		mapPaths = v.expandField(mapField)
+	}
+
+	for _, mapPath := range mapPaths {
+		// the condition of the map path is false
+		if r.fieldCond != nil && !r.fieldCond(v, mapPath) {
 			continue
 		}
 
-		// apply filter func.
-		if exist && r.filterFunc != nil {
-			if val, err = r.filterFunc(val); err != nil { // has error
-				v.AddError(filterError, filterError, err.Error())
+		for _, key := range v.elemKeys(mapPath) {
+			if v.ctxDone() {
 				return true
 			}
 
-			// update source field value
-			newVal, err := v.updateValue(field, val)
-			if err != nil {
-				// panicf(err.Error())
-				v.AddErrorf(field, err.Error())
-				if v.StopOnError {
-					return true
-				}
+			// empty value AND skip on empty.
+			if r.skipEmpty && r.nameNotRequired && key == "" {
 				continue
 			}
 
-			// re-set value
-			val = newVal
-			// save filtered value.
-			v.filteredData[field] = val
+			field := mapPath + "." + key
+			if ok, args := r.valueValidate(field, r.realName, key, v); !ok {
+				r.addError(v, field, pattern, key, args)
+				if v.shouldStop() {
+					return true
+				}
+			}
 		}
+	}
+	return false
+}
 
-		// empty value AND skip on empty.
-		if r.skipEmpty && isNotRequired && IsEmpty(val) {
-			continue
-		}
+// add a field error for the rule
+func (r *Rule) addError(v *Validation, field, pattern string, val interface{}, args []interface{}) {
+	fe := &FieldError{
+		Field:     field,
+		Validator: r.validator,
+		// copy args, the rule args maybe shared by the cache.
+		Args:  append([]interface{}(nil), args...),
+		Value: val,
+	}
+
+	// the field values cannot be compared. eg: time.Time with int
+	if err := r.compareError(v, val, args); err != nil {
+		fe.Err = err
+		fe.Message = v.fieldMessage(compareError, field, pattern, args[0], err.Error())
+	} else {
+		fe.Message = r.errorMessage(field, pattern, r.validator, v, args...)
+	}
+	v.AddFieldError(fe)
+}
 
-		// validate field value
-		if r.valueValidate(field, name, val, v) {
-			v.safeData[field] = val // save validated value.
-		} else { // build and collect error message
-			v.AddError(field, r.validator, r.errorMessage(field, r.validator, v))
+// compareError check the field compare validator is failed by the values cannot be compared.
+func (r *Rule) compareError(v *Validation, val interface{}, args []interface{}) error {
+	switch r.realName {
+	case "gtField", "gteField", "ltField", "lteField":
+	case "eqField", "neField":
+		if !isTimeValue(val) {
+			return nil
 		}
+	default:
+		return nil
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	dstField, ok := args[0].(string)
+	if !ok {
+		return nil
+	}
+
+	// the dst field not exists, is not a compare error.
+	dstVal, has := v.Get(dstField)
+	if !has {
+		return nil
+	}
+
+	_, err := compareValues(val, dstVal, v.LexicalCompare)
+	return err
+}
 
-		// stop on error
-		if v.shouldStop() {
+// anyOfValidate validate the value by the alternatives of the group, passed if any of them is passed.
+// on failed, will add an error lists all alternatives, the Err of it contains the errors of the alternatives.
+func (r *Rule) anyOfValidate(field, pattern string, val interface{}, v *Validation) bool {
+	var errs FieldErrors
+	names := make([]string, 0, len(r.anyOf))
+	for _, alt := range r.anyOf {
+		ok, args := alt.valueValidate(field, alt.realName, val, v)
+		if ok {
 			return true
 		}
+
+		names = append(names, alt.String())
+		errs = append(errs, &FieldError{
+			Field:     field,
+			Validator: alt.validator,
+			Args:      append([]interface{}(nil), args...),
+			Value:     val,
+			Message:   alt.errorMessage(field, pattern, alt.validator, v, args...),
+		})
 	}
 
+	args := strings2Args(names)
+	v.AddFieldError(&FieldError{
+		Field:     field,
+		Validator: r.validator,
+		Args:      args,
+		Value:     val,
+		Message:   r.errorMessage(field, pattern, r.validator, v, strings.Join(names, ", ")),
+		Err:       errs,
+	})
 	return false
 }
 
@@ -207,11 +401,11 @@ func (r *Rule) fileValidate(field, name string, v *Validation) uint8 {
 	return statusFail
 }
 
-// validate the field value
-func (r *Rule) valueValidate(field, name string, val interface{}, v *Validation) bool {
+// validate the field value. returns the converted arguments for build error message.
+func (r *Rule) valueValidate(field, name string, val interface{}, v *Validation) (bool, []interface{}) {
 	// "-" OR "safe" mark field value always is safe.
 	if name == "-" || name == "safe" {
-		return true
+		return true, r.arguments
 	}
 
 	// call custom validator in the rule.
@@ -233,16 +427,14 @@ func (r *Rule) valueValidate(field, name string, val interface{}, v *Validation)
 		//noinspection GoNilness
 		fm.checkArgNum(argNum, r.validator)
 
-		//noinspection GoNilness
-		ft := fm.fv.Type()
 		// convert field val type, is first argument.
-		firstTyp := ft.In(0).Kind()
+		firstTyp := fm.argType(0).Kind()
 		if firstTyp != valKind && firstTyp != reflect.Interface {
 			ak, err := basicKind(rftVal)
 			if err != nil { // todo check?
 				//noinspection GoNilness
 				v.convArgTypeError(field, fm.name, valKind, firstTyp, 0)
-				return false
+				return false, r.arguments
 			}
 
 			// manual converted
@@ -252,21 +444,37 @@ func (r *Rule) valueValidate(field, name string, val interface{}, v *Validation)
 		}
 	}
 
-	// 1. args data type convert
-	args := r.arguments
-	if ok := convertArgsType(v, fm, field, args); !ok {
-		return false
+	// 1. args data type convert.
+	// NOTICE: dont modify r.arguments, the rule maybe shared by multi goroutines. see Schema
+	args, ok := convertArgsType(v, fm, field, r.arguments)
+	if !ok {
+		return false, r.arguments
 	}
 
 	// 2. call built in validators
-	return callValidator(v, fm, field, val, r.arguments)
+	return callValidator(v, fm, field, val, args), args
 }
 
 func callValidator(v *Validation, fm *funcMeta, field string, val interface{}, args []interface{}) (ok bool) {
+	// user custom validators, maybe has same name as the built-in validator.
+	if !fm.isInternal {
+		return callValidatorValue(v.context(), fm, val, args)
+	}
+
+	// the bounds must be number. eg: "min:0.01"
+	switch fm.name {
+	case "lt", "gt", "min", "max", "between":
+		if !checkNumberArgs(v, fm, field, args) {
+			return false
+		}
+	}
+
 	// use `switch` can avoid using reflection to call methods and improve speed
 	switch fm.name {
 	case "required":
 		ok = v.Required(field, val)
+	case "requiredKey":
+		ok = v.RequiredKey(field, val)
 	case "requiredIf":
 		ok = v.RequiredIf(field, val, args2strings(args)...)
 	case "requiredUnless":
@@ -280,13 +488,13 @@ func callValidator(v *Validation, fm *funcMeta, field string, val interface{}, a
 	case "requiredWithoutAll":
 		ok = v.RequiredWithoutAll(field, val, args2strings(args)...)
 	case "lt":
-		ok = Lt(val, args[0].(int64))
+		ok = LtNumber(val, args[0])
 	case "gt":
-		ok = Gt(val, args[0].(int64))
+		ok = GtNumber(val, args[0])
 	case "min":
-		ok = Min(val, args[0].(int64))
+		ok = MinNumber(val, args[0])
 	case "max":
-		ok = Max(val, args[0].(int64))
+		ok = MaxNumber(val, args[0])
 	case "enum":
 		ok = Enum(val, args[0])
 	case "notIn":
@@ -326,23 +534,43 @@ func callValidator(v *Validation, fm *funcMeta, field string, val interface{}, a
 	case "regexp":
 		ok = Regexp(val.(string), args[0].(string))
 	case "between":
-		ok = Between(val, args[0].(int64), args[1].(int64))
+		ok = BetweenNumber(val, args[0], args[1])
 	case "isJSON":
 		ok = IsJSON(val.(string))
 	default:
 		// 3. call user custom validators, will call by reflect
-		ok = callValidatorValue(fm.fv, val, args)
+		ok = callValidatorValue(v.context(), fm, val, args)
 	}
 	return
 }
 
-// convert args data type
-func convertArgsType(v *Validation, fm *funcMeta, field string, args []interface{}) (ok bool) {
+// checkNumberArgs check the args are int, uint, float or numeric string.
+func checkNumberArgs(v *Validation, fm *funcMeta, field string, args []interface{}) bool {
+	for i, arg := range args {
+		if _, ok := toRat(arg); arg == nil || !ok {
+			v.convArgTypeError(field, fm.name, reflect.ValueOf(arg).Kind(), reflect.Float64, i+1)
+			return false
+		}
+	}
+	return true
+}
+
+// convert args data type. will return a new slice on some arg has been converted.
+func convertArgsType(v *Validation, fm *funcMeta, field string, srcArgs []interface{}) (args []interface{}, ok bool) {
+	args = srcArgs
 	if len(args) == 0 {
-		return true
+		return args, true
+	}
+
+	// copy on write
+	setArg := func(i int, arg interface{}) {
+		if &args[0] == &srcArgs[0] {
+			args = make([]interface{}, len(srcArgs))
+			copy(args, srcArgs)
+		}
+		args[i] = arg
 	}
 
-	ft := fm.fv.Type()
 	lastTyp := reflect.Invalid
 	lastArgIndex := fm.numIn - 1
 
@@ -350,12 +578,12 @@ func convertArgsType(v *Validation, fm *funcMeta, field string, args []interface
 	// eg. "...int64" -> slice "[]int64"
 	if fm.isVariadic {
 		// get variadic kind. "[]int64" -> reflect.Int64
-		lastTyp = getVariadicKind(ft.In(lastArgIndex).String())
+		lastTyp = getVariadicKind(fm.argType(lastArgIndex).String())
 	}
 
 	// only one args and it type is interface{}
 	if lastArgIndex == 1 && lastTyp == reflect.Interface {
-		return true
+		return args, true
 	}
 
 	var wantTyp reflect.Kind
@@ -381,7 +609,7 @@ func convertArgsType(v *Validation, fm *funcMeta, field string, args []interface
 
 			// manual converted
 			if nVal, _ := convertType(args[i], ak, lastTyp); nVal != nil {
-				args[i] = nVal
+				setArg(i, nVal)
 				continue
 			}
 
@@ -391,7 +619,7 @@ func convertArgsType(v *Validation, fm *funcMeta, field string, args []interface
 		}
 
 		// "+1" because func first arg is val, need skip it.
-		argITyp := ft.In(fcArgIndex)
+		argITyp := fm.argType(fcArgIndex)
 		wantTyp = argITyp.Kind()
 
 		// type is same. or want type is interface
@@ -406,30 +634,44 @@ func convertArgsType(v *Validation, fm *funcMeta, field string, args []interface
 		}
 
 		if av.Type().ConvertibleTo(argITyp) { // can auto convert type.
-			args[i] = av.Convert(argITyp).Interface()
+			setArg(i, av.Convert(argITyp).Interface())
 		} else if nVal, _ := convertType(args[i], ak, wantTyp); nVal != nil { // manual converted
-			args[i] = nVal
+			setArg(i, nVal)
 		} else { // unable to convert
 			v.convArgTypeError(field, fm.name, av.Kind(), wantTyp, fcArgIndex)
 			return
 		}
 	}
 
-	return true
+	return args, true
 }
 
-func callValidatorValue(fv reflect.Value, val interface{}, args []interface{}) bool {
+func callValidatorValue(ctx context.Context, fm *funcMeta, val interface{}, args []interface{}) bool {
 	argNum := len(args)
 
 	// build params for the validator func.
-	argIn := make([]reflect.Value, argNum+1)
-	argIn[0] = reflect.ValueOf(val)
+	argIn := make([]reflect.Value, 0, argNum+2)
+	if fm.withCtx { // first arg is context.Context
+		argIn = append(argIn, reflect.ValueOf(&ctx).Elem())
+	}
+
+	// the nil value is as the zero value of the param type. eg: JSON null
+	valIn := reflect.ValueOf(val)
+	if val == nil {
+		ft, idx := fm.fv.Type(), len(argIn)
+		if ft.IsVariadic() && idx == ft.NumIn()-1 {
+			valIn = reflect.Zero(ft.In(idx).Elem())
+		} else {
+			valIn = reflect.Zero(ft.In(idx))
+		}
+	}
 
+	argIn = append(argIn, valIn)
 	for i := 0; i < argNum; i++ {
-		argIn[i+1] = reflect.ValueOf(args[i])
+		argIn = append(argIn, reflect.ValueOf(args[i]))
 	}
 
 	// NOTICE: f.CallSlice()与Call() 不一样的是，CallSlice参数的最后一个会被展开
 	// vs := fv.Call(argIn)
-	return fv.Call(argIn)[0].Bool()
+	return fm.fv.Call(argIn)[0].Bool()
 }
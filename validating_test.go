@@ -1,6 +1,7 @@
 package validate
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -63,6 +64,49 @@ func TestStructUseDefault(t *testing.T) {
 	is.Equal("TOM", u.Name)
 }
 
+func TestMapUseDefault(t *testing.T) {
+	is := assert.New(t)
+
+	// the default value is not checked by all rules of the field
+	v := Map(M{"name": "inhere"})
+	v.StringRules(MS{"timeout": "default:300|int|max:60"})
+	v.AddRule("timeout", "min", 100)
+	is.True(v.Validate())
+	is.Equal("300", v.SafeVal("timeout"))
+
+	v = Map(M{"timeout": 300})
+	v.StringRules(MS{"timeout": "default:30|int|max:60"})
+	is.False(v.Validate())
+
+	v = Map(M{"name": "inhere"})
+	v.CheckDefault = true
+	v.StringRules(MS{"timeout": "default:300|max:60"})
+	is.False(v.Validate())
+	is.Equal("max", v.FieldErrors()[0].Validator)
+}
+
+func TestValidation_RequiredKey(t *testing.T) {
+	is := assert.New(t)
+	v := New(M{
+		"count":   0,
+		"enabled": false,
+		"name":    "",
+		"nothing": nil,
+	})
+	v.StringRules(MS{
+		"count":   "requiredKey",
+		"enabled": "required_key",
+		"name":    "requiredKey",
+		"nothing": "requiredKey",
+	})
+	is.True(v.Validate())
+
+	v = New(M{"name": ""})
+	v.StringRule("age", "requiredKey")
+	is.False(v.Validate())
+	is.Equal("age is required", v.Errors.One())
+}
+
 func TestValidation_RequiredIf(t *testing.T) {
 	v := New(M{
 		"name": "lee",
@@ -177,3 +221,257 @@ func TestVariadicArgs(t *testing.T) {
 	ok := v.Validate()
 	assert.True(t, ok)
 }
+
+func TestWildcardField_MapData(t *testing.T) {
+	is := assert.New(t)
+	mp := M{
+		"items": []interface{}{
+			M{"name": "a", "price": 23},
+			M{"name": "b", "price": 0},
+			M{"name": "c", "price": -1},
+		},
+		"tags": []string{"go", ""},
+		"attrs": map[string]int{"b": 2, "a": 1},
+	}
+
+	v := Map(mp)
+	v.StopOnError = false
+	v.StringRules(MS{
+		"items.*.price": "required|min:1",
+		"tags.*":        "required|minLen:2",
+		"attrs.*":       "max:1",
+	})
+	v.AddMessages(MS{"items.*.price.min": "{field} is too small"})
+	v.AddTranslates(MS{"tags.*": "Tag"})
+
+	is.False(v.Validate())
+	is.Len(v.Errors, 4)
+	is.Equal("items.1.price is required and not empty", v.Errors.FieldOne("items.1.price"))
+	is.Equal("items.2.price is too small", v.Errors.FieldOne("items.2.price"))
+	is.Equal("Tag is required and not empty", v.Errors.FieldOne("tags.1"))
+	is.Equal("attrs.b max value is 1", v.Errors.FieldOne("attrs.b"))
+
+	// no elements
+	v = Map(M{"items": []interface{}{}})
+	v.StringRule("items.*.price", "required")
+	is.True(v.Validate())
+
+	v = Map(M{"items": []interface{}{M{"price": 23}}})
+	v.StringRule("items.*.price", "required|min:1")
+	is.True(v.Validate())
+	is.Equal(23, v.SafeVal("items.0.price"))
+
+	// get value by index path
+	v = Map(mp)
+	val, ok := v.Get("items.0.name")
+	is.True(ok)
+	is.Equal("a", val)
+	_, ok = v.Get("items.3.name")
+	is.False(ok)
+	val, ok = v.Get("attrs.a")
+	is.True(ok)
+	is.Equal(1, val)
+}
+
+func TestWildcardField_FormData(t *testing.T) {
+	is := assert.New(t)
+	d := FromURLValues(map[string][]string{
+		"tags":          {"go", "x"},
+		"items.0.price": {"12"},
+		"items.1.price": {"abc"},
+	})
+
+	v := d.Create()
+	v.StopOnError = false
+	v.StringRules(MS{
+		"tags.*":        "minLen:2",
+		"items.*.price": "required|strInt",
+	})
+	v.FilterRule("tags.*", "upper")
+
+	is.False(v.Validate())
+	is.Len(v.Errors, 2)
+	is.Contains(v.Errors, "tags.1")
+	is.Contains(v.Errors, "items.1.price")
+	is.Equal("GO", v.FilteredData()["tags.0"])
+	is.Equal("X", v.FilteredData()["tags.1"])
+}
+
+func TestWildcardField_FormData_indexOrder(t *testing.T) {
+	is := assert.New(t)
+	d := FromURLValues(map[string][]string{
+		"items.10.price": {"a"},
+		"items.2.price":  {"b"},
+		"items.1.price":  {"12"},
+		"items.x.price":  {"c"},
+	})
+	is.Equal([]string{"1", "2", "10", "x"}, d.elemKeys("items"))
+
+	v := d.Create()
+	v.StopOnError = false
+	v.StringRule("items.*.price", "strInt")
+
+	is.False(v.Validate())
+	is.Equal([]string{"items.2.price", "items.10.price", "items.x.price"}, v.FieldErrors().Fields())
+}
+
+func TestWildcardField_StructData(t *testing.T) {
+	is := assert.New(t)
+
+	type item struct {
+		Name  string `validate:"required"`
+		Price int    `validate:"min:1"`
+	}
+	type order struct {
+		Items  []*item
+		Groups map[string][]item
+		Tags   []string
+	}
+
+	o := &order{
+		Items: []*item{{"a", 2}, {"", 1}},
+		Groups: map[string][]item{
+			"g1": {{"a", 1}},
+		},
+		Tags: []string{"go", ""},
+	}
+
+	v := Struct(o)
+	v.StopOnError = false
+	v.StringRule("Tags.*", "required")
+	v.StringRule("Groups.*.*.Price", "min:2")
+
+	is.False(v.Validate())
+	is.Contains(v.Errors, "Items.1.Name")
+	is.Contains(v.Errors, "Groups.g1.0.Price")
+	is.Contains(v.Errors, "Tags.1")
+	is.Len(v.Errors, 3)
+
+	// no elements
+	v = Struct(&order{})
+	is.True(v.Validate())
+}
+
+func TestMapKeyRules(t *testing.T) {
+	is := assert.New(t)
+	mp := M{
+		"labels": map[string]interface{}{
+			"env":      "prod",
+			"bad key!": "x",
+		},
+		"groups": M{
+			"g1": M{"ok": 1, "a.b": 2},
+		},
+	}
+
+	v := Map(mp)
+	v.StopOnError = false
+	v.StringRule("labels", "required")
+	v.StringRule("labels.#key", "alphaDash|maxLen:32")
+	v.StringRule("labels.*", "minLen:2")
+	v.AddTranslates(MS{"labels.#key": "label key"})
+
+	is.False(v.Validate())
+	// the key and value errors are reported by the key path
+	is.Len(v.Errors, 1)
+	is.Equal("label key value contains only letters,num,dashes (-) and underscores (_)", v.Errors["labels.bad key!"]["alphaDash"])
+	is.Equal("labels.bad key! min length is 2", v.Errors["labels.bad key!"]["minLen"])
+
+	// nested map keys
+	v = Map(mp)
+	v.StringRule("groups.*.#key", "isAlpha")
+	is.False(v.Validate())
+	is.Contains(v.Errors, "groups.g1.a.b")
+
+	type config struct {
+		Labels map[string]string `validate:"required" validateKey:"isAlphaDash|maxLen:5" message:"maxLen:key is too long"`
+	}
+
+	v = Struct(&config{Labels: map[string]string{"app": "demo"}})
+	is.True(v.Validate())
+
+	v = Struct(&config{Labels: map[string]string{"app": "demo", "too-long": "v"}})
+	is.False(v.Validate())
+	is.Equal("key is too long", v.Errors.FieldOne("Labels.too-long"))
+}
+
+type ctxKey string
+
+func TestValidation_ValidateContext(t *testing.T) {
+	is := assert.New(t)
+
+	var calls int
+	v := Map(M{"name": "inhere", "age": 20})
+	v.StopOnError = false
+	v.AddValidator("inCache", func(ctx context.Context, val interface{}, prefix string) bool {
+		calls++
+		return ctx.Value(ctxKey("cache")) == prefix+val.(string)
+	})
+	v.AddRule("name", "inCache", "user:")
+
+	ctx := context.WithValue(context.Background(), ctxKey("cache"), "user:inhere")
+	is.True(v.ValidateContext(ctx))
+	is.Equal(1, calls)
+
+	// without context, use the context.Background()
+	v = Map(M{"name": "inhere"})
+	v.AddValidator("inCache", func(ctx context.Context, val interface{}) bool {
+		return ctx != nil && ctx.Value(ctxKey("cache")) == nil
+	})
+	v.StringRule("name", "inCache")
+	is.True(v.Validate())
+
+	// canceled, stop the rule loop
+	calls = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	v = Map(M{"name": "inhere", "age": 20})
+	v.StopOnError = false
+	v.AddValidator("cancelIt", func(ctx context.Context, val interface{}) bool {
+		calls++
+		cancel()
+		return true
+	})
+	v.AddRule("name", "cancelIt")
+	v.AddRule("age", "cancelIt")
+
+	is.False(v.ValidateContext(ctx))
+	is.Equal(1, calls)
+	is.True(errorIs(v.Err(), context.Canceled))
+	is.Equal("validate is aborted: context canceled", v.Errors.FieldOne("_context"))
+
+	fes := v.FieldErrors()
+	is.Len(fes, 1)
+	is.Equal("_context", fes[0].Validator)
+	is.Equal(context.Canceled, fes[0].Unwrap())
+
+	// canceled in the middle of the wildcard paths
+	items := make([]interface{}, 100)
+	for i := range items {
+		items[i] = M{"name": "item"}
+	}
+	for _, concurrency := range []int{0, 2} {
+		calls = 0
+		ctx, cancel = context.WithCancel(context.Background())
+		v = Map(M{"items": items, "age": 20})
+		v.Concurrency = concurrency
+		v.AddValidator("cancelIt", func(ctx context.Context, val interface{}) bool {
+			calls++
+			cancel()
+			return true
+		})
+		v.AddRule("items.*.name", "cancelIt")
+
+		is.False(v.ValidateContext(ctx))
+		is.Equal(1, calls)
+		is.Len(v.FieldErrors(), 1)
+		is.True(errorIs(v.Err(), context.Canceled))
+	}
+
+	// schema
+	s := NewSchema(func(v *Validation) {
+		v.StringRule("name", "required")
+	})
+	res := s.ValidateContext(ctx, FromMap(M{"name": "inhere"}), "")
+	is.True(res.IsFail())
+	is.True(errorIs(res.Err(), context.Canceled))
+}
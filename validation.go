@@ -1,8 +1,11 @@
 package validate
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 )
 
 // some default value settings.
@@ -12,9 +15,17 @@ const (
 
 	messageTag  = "message"
 	validateTag = "validate"
+	// tag name for validate the map keys
+	keyTag = "validateKey"
+	// path suffix for validate the map keys. eg: "labels.#key"
+	keyPathSuffix = ".#key"
 
 	filterError   = "_filter"
 	validateError = "_validate"
+	contextError  = "_context"
+	compareError  = "_compare"
+	// validator name of the rule group. eg: "(isEmail or isCnMobile)"
+	anyOfName = "anyOf"
 	// sniff Length, use for detect file mime type
 	sniffLen = 512
 	// 32 MB
@@ -35,8 +46,10 @@ type Validation struct {
 	safeData M
 	// filtered clean data
 	filteredData M
-	// Errors for the validate
+	// Errors for the validate. it is a compatible view of the field errors.
 	Errors Errors
+	// structured errors for the validate, keep the order of occurred.
+	fieldErrors FieldErrors
 	// CacheKey for cache rules
 	// CacheKey string
 	// StopOnError If true: An error occurs, it will cease to continue to verify
@@ -47,6 +60,15 @@ type Validation struct {
 	UpdateSource bool
 	// CheckDefault Whether to validate the default value set by the user
 	CheckDefault bool
+	// LexicalCompare Whether compare the plain strings by lexical in the field compare validators.
+	// default is compare by the length. eg: "gtField:name"
+	LexicalCompare bool
+	// Concurrency the max workers for validate the fields in parallel.
+	// the rules of one field still run in order. default is 0: validate in sequence.
+	Concurrency int
+	// Clock returns the current time, use for the relative time validators. eg: "after:now"
+	// default is time.Now. can set a fixed time for testing.
+	Clock func() time.Time
 	// CachingRules switch. default is False
 	// CachingRules bool
 	// save user set default values
@@ -67,6 +89,8 @@ type Validation struct {
 	validatorValues map[string]reflect.Value
 	// translator instance
 	trans *Translator
+	// the registry of the global validators, filters and messages
+	reg *Registry
 	// current scene name
 	scene string
 	// scenes config.
@@ -81,6 +105,10 @@ type Validation struct {
 	filterRules []*FilterRule
 	// filter func reflect.Value map
 	filterValues map[string]reflect.Value
+	// context for the ValidateContext()
+	ctx context.Context
+	// the validate is aborted by the context done
+	aborted bool
 }
 
 // NewEmpty new validation instance, but not add data.
@@ -110,12 +138,34 @@ func NewValidation(data DataFace, scene ...string) *Validation {
 // ResetResult reset the validate result.
 func (v *Validation) ResetResult() {
 	v.Errors = Errors{}
-	v.hasError = false
-	v.hasFiltered = false
-	v.hasValidated = false
 	// result data
 	v.safeData = make(map[string]interface{})
 	v.filteredData = make(map[string]interface{})
+	v.resetStatus()
+}
+
+// clearResult like the ResetResult(), but the maps are cleared for reuse, avoid the allocation for each record.
+// NOTICE: the maps of the previous result are cleared too. see Schema.ValidateCSV()
+func (v *Validation) clearResult() {
+	for field := range v.Errors {
+		delete(v.Errors, field)
+	}
+	for field := range v.safeData {
+		delete(v.safeData, field)
+	}
+	for field := range v.filteredData {
+		delete(v.filteredData, field)
+	}
+	v.resetStatus()
+}
+
+func (v *Validation) resetStatus() {
+	// the field errors maybe kept by the previous result, dont reuse it.
+	v.fieldErrors = nil
+	v.hasError = false
+	v.hasFiltered = false
+	v.hasValidated = false
+	v.aborted = false
 }
 
 // Reset the Validation instance
@@ -197,7 +247,7 @@ func (v *Validation) validatorMeta(name string) *funcMeta {
 	}
 
 	// from global validators
-	if fm, ok := validatorMetas[name]; ok {
+	if fm, ok := v.reg.validatorMeta(name); ok {
 		return fm
 	}
 
@@ -218,7 +268,7 @@ func (v *Validation) validatorMeta(name string) *funcMeta {
 
 // HasValidator check
 func (v *Validation) HasValidator(name string) bool {
-	name = ValidatorName(name)
+	name = v.reg.ValidatorName(name)
 
 	// current validation
 	if _, ok := v.validatorMetas[name]; ok {
@@ -226,17 +276,14 @@ func (v *Validation) HasValidator(name string) bool {
 	}
 
 	// global validators
-	_, ok := validatorMetas[name]
+	_, ok := v.reg.validatorMeta(name)
 	return ok
 }
 
 // Validators get all validator names
 func (v *Validation) Validators(withGlobal bool) map[string]int8 {
 	if withGlobal {
-		mp := make(map[string]int8)
-		for name, typ := range validators {
-			mp[name] = typ
-		}
+		mp := v.reg.Validators()
 
 		for name, typ := range v.validators {
 			mp[name] = typ
@@ -321,11 +368,57 @@ func (v *Validation) WithError(err error) *Validation {
 
 // AddError message for a field
 func (v *Validation) AddError(field, validator, msg string) {
+	v.AddFieldError(&FieldError{Field: field, Validator: validator, Message: msg})
+}
+
+// AddFieldError add a structured error for the field
+func (v *Validation) AddFieldError(fe *FieldError) {
 	if !v.hasError {
 		v.hasError = true
 	}
 
-	v.Errors.Add(field, validator, msg)
+	// add the source position of the field. copy it, the error maybe shared by the Schema.
+	if pf, ok := v.data.(PositionFace); ok && fe.Pos == nil {
+		if pos, has := pf.Position(fe.Field); has {
+			cp := *fe
+			cp.Pos = &pos
+			fe = &cp
+		}
+	}
+
+	v.fieldErrors = append(v.fieldErrors, fe)
+	v.Errors.Add(fe.Field, fe.Validator, fe.Message)
+}
+
+// FieldErrors get all structured errors, the order is same as the errors occurred.
+func (v *Validation) FieldErrors() FieldErrors {
+	return v.fieldErrors
+}
+
+// Err returns the validate error. it is a FieldErrors on has error, otherwise nil.
+func (v *Validation) Err() error {
+	if !v.hasError {
+		return nil
+	}
+	return v.fieldErrors
+}
+
+// ctxDone check the context is canceled or deadline exceeded, mark the validate is aborted on done.
+func (v *Validation) ctxDone() bool {
+	if !v.aborted && v.ctx != nil && v.ctx.Err() != nil {
+		v.aborted = true
+	}
+	return v.aborted
+}
+
+// add the context done error, the validate is aborted.
+func (v *Validation) addContextError(err error) {
+	v.AddFieldError(&FieldError{
+		Field:     contextError,
+		Validator: contextError,
+		Message:   v.trans.Message(contextError, contextError, err.Error()),
+		Err:       err,
+	})
 }
 
 // AddErrorf add a formatted error message
@@ -386,6 +479,93 @@ func (v *Validation) GetWithDefault(key string) (val interface{}, exist, isDefau
 	return
 }
 
+// get field value with default value. the default value can be set by the wildcard pattern.
+func (v *Validation) getWithDefault(field, pattern string) (val interface{}, exist, isDefault bool) {
+	val, exist, isDefault = v.GetWithDefault(field)
+	if exist || isDefault || field == pattern {
+		return
+	}
+
+	val, isDefault = v.defValues[pattern]
+	return
+}
+
+// isDefaultSaved check the default value of the field has been saved to the safe data by the previous rule.
+// the field is not in the source data and filtered data, so the safe value is the default value.
+func (v *Validation) isDefaultSaved(field, pattern string) bool {
+	if _, ok := v.safeData[field]; !ok || v.data == nil {
+		return false
+	}
+
+	if _, ok := v.defValues[field]; !ok {
+		if _, ok = v.defValues[pattern]; !ok {
+			return false
+		}
+	}
+
+	if _, ok := v.filteredData[field]; ok {
+		return false
+	}
+
+	_, ok := v.data.Get(field)
+	return !ok
+}
+
+// expandField expand the wildcard field to real field paths.
+// Usage:
+// 	"items.*.price" -> "items.0.price", "items.1.price"
+// 	"tags.*" -> "tags.0", "tags.1"
+func (v *Validation) expandField(field string) (paths []string) {
+	if v.data == nil {
+		return
+	}
+
+	nodes := strings.Split(field, ".")
+
+	var expandFunc func(path string, i int)
+	expandFunc = func(path string, i int) {
+		if i == len(nodes) {
+			paths = append(paths, path)
+			return
+		}
+
+		node := nodes[i]
+		if node != "*" {
+			if path != "" {
+				node = path + "." + node
+			}
+			expandFunc(node, i+1)
+			return
+		}
+
+		// the wildcard at first is not supported.
+		if path == "" {
+			return
+		}
+
+		for _, key := range v.elemKeys(path) {
+			expandFunc(path+"."+key, i+1)
+		}
+	}
+
+	expandFunc("", 0)
+	return
+}
+
+// get element keys of the slice/map field, will use for expand wildcard.
+func (v *Validation) elemKeys(field string) []string {
+	// the data source can list the element keys. eg: FormData
+	if el, ok := v.data.(elemKeysLister); ok {
+		return el.elemKeys(field)
+	}
+
+	val, exist := v.Get(field)
+	if !exist {
+		return nil
+	}
+	return valueElemKeys(val)
+}
+
 // Filtered get filtered value by key
 func (v *Validation) Filtered(key string) interface{} {
 	val, _ := v.filteredData[key]
@@ -472,11 +652,41 @@ func (v *Validation) GetDefValue(field string) (interface{}, bool) {
 	return defVal, ok
 }
 
+// get the context for call validators, default is context.Background()
+func (v *Validation) context() context.Context {
+	if v.ctx == nil {
+		return context.Background()
+	}
+	return v.ctx
+}
+
+// get the current time by the Clock, default is time.Now()
+func (v *Validation) now() time.Time {
+	if v.Clock == nil {
+		return time.Now()
+	}
+	return v.Clock()
+}
+
 // Trans get message Translator
 func (v *Validation) Trans() *Translator {
 	return v.trans
 }
 
+// fieldMessage get the error message for the field.
+// the data source maybe provides the field names. eg: EnvData
+func (v *Validation) fieldMessage(validator, field, pattern string, args ...interface{}) string {
+	fn, ok := v.data.(fieldNamer)
+	if !ok {
+		return v.trans.fieldMessage(validator, field, pattern, args...)
+	}
+
+	// the translator maybe shared by the Schema, use a copy.
+	t := *v.trans
+	t.namer = fn
+	return t.fieldMessage(validator, field, pattern, args...)
+}
+
 // SceneFields field names get
 func (v *Validation) SceneFields() []string {
 	return v.scenes[v.scene]
@@ -2,6 +2,7 @@ package validate
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"mime/multipart"
 	"net/http"
@@ -64,7 +65,7 @@ func TestMap(t *testing.T) {
 	v.AddRule("age", "max", nil)
 	// v.AddRule("age", "max", []string{"a"})
 	is.False(v.Validate())
-	is.Contains(v.Errors.String(), "cannot convert invalid to arg#1(int64)")
+	is.Contains(v.Errors.String(), "cannot convert invalid to arg#1(float64)")
 
 	v = New(mpSample)
 	v.StringRule("newSt", "") // will ignore
@@ -296,6 +297,39 @@ func TestJSON(t *testing.T) {
 	is.Equal("inhere", v.SafeData()["name"])
 }
 
+func TestJSON_useNumber(t *testing.T) {
+	is := assert.New(t)
+
+	jsonStr := `{"age": 20, "amount": 12345678901234567890.12}`
+	d, err := FromJSON(jsonStr)
+	is.NoError(err)
+	is.IsType(float64(0), d.Map["amount"])
+
+	Config(func(opt *GlobalOption) {
+		opt.UseNumber = true
+	})
+	defer ResetOption()
+
+	d, err = FromJSON(jsonStr)
+	is.NoError(err)
+	is.Equal(json.Number("12345678901234567890.12"), d.Map["amount"])
+
+	v := d.Create()
+	v.StopOnError = false
+	v.StringRules(MS{
+		"age":    "required|int|uint|float|min:18",
+		"amount": "required|isDecimal:22,2|between:0,12345678901234567890.11",
+	})
+	is.False(v.Validate())
+	is.Equal([]string{"amount"}, v.FieldErrors().Fields())
+	is.Equal("amount value must be in the range 0 - 12345678901234567890.11", v.Errors.One())
+
+	_, err = FromJSON(`{"age": 20} {}`)
+	is.Error(err)
+	_, err = FromJSON(`invalid`)
+	is.Error(err)
+}
+
 func TestFromQuery(t *testing.T) {
 	is := assert.New(t)
 	data := url.Values{
@@ -587,6 +621,76 @@ func TestFieldCompare(t *testing.T) {
 	is.Contains(emp, "newSt")
 }
 
+func TestFieldCompare_types(t *testing.T) {
+	is := assert.New(t)
+	start := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	// time.Time
+	type booking struct {
+		StartAt time.Time `validate:"required"`
+		EndAt   time.Time `validate:"required|gtField:StartAt"`
+	}
+	v := Struct(&booking{StartAt: start, EndAt: start.Add(time.Hour)})
+	is.True(v.Validate())
+
+	v = Struct(&booking{StartAt: start, EndAt: start.Add(-time.Hour)})
+	is.False(v.Validate())
+	is.Equal("EndAt value must be greater the field StartAt", v.Errors.One())
+
+	// date strings, time.Time with date string
+	v = Map(M{"start": "2021-05-01", "end": "2021-05-03 10:00:00", "at": start})
+	v.StopOnError = false
+	v.StringRules(MS{
+		"end": "gtField:start|gteField:at",
+		"at":  "gtField:start|ltField:end|eqField:start",
+	})
+	v.AddRule("at", "neField", "end")
+	is.False(v.Validate())
+	is.Equal([]string{"at"}, v.FieldErrors().Fields())
+	is.Equal("eqField", v.FieldErrors()[0].Validator)
+
+	// plain strings, numeric strings, floats
+	v = Map(M{"a": "apple", "b": "banana", "n1": "10", "n2": "9", "f1": 1.5, "f2": 1, "f3": "1.25"})
+	v.StringRules(MS{
+		"b":  "gtField:a",
+		"n1": "gtField:n2",
+		"f1": "gtField:f2|gtField:f3",
+		"f3": "ltField:f1|gtField:f2",
+	})
+	is.True(v.Validate())
+
+	// the plain strings are compared by length on default, by lexical on enabled.
+	mp := M{"a": "apple", "b": "zoo"}
+	v = Map(mp)
+	v.StringRule("b", "ltField:a")
+	is.True(v.Validate())
+
+	v = Map(mp)
+	v.LexicalCompare = true
+	v.StringRule("b", "gtField:a")
+	is.True(v.Validate())
+	v = Map(mp)
+	v.LexicalCompare = true
+	v.StringRule("b", "ltField:a")
+	is.False(v.Validate())
+
+	s := v.Compile()
+	is.False(s.Validate(FromMap(mp), "").IsOK())
+	is.True(s.Validate(FromMap(M{"a": "zoo", "b": "apple"}), "").IsOK())
+
+	// type mismatch
+	v = Map(M{"start": start, "num": 23, "name": "abc"})
+	v.StopOnError = false
+	v.StringRules(MS{
+		"num":  "gtField:start",
+		"name": "ltField:num",
+	})
+	is.False(v.Validate())
+	is.Equal("num value cannot compare with the field start: cannot compare int with time.Time", v.Errors.FieldOne("num"))
+	is.Equal("name value cannot compare with the field num: cannot compare string with int", v.Errors.FieldOne("name"))
+	is.Error(v.FieldErrors()[0].Err)
+}
+
 func TestValidationScene(t *testing.T) {
 	is := assert.New(t)
 	mp := M{
@@ -3,6 +3,8 @@ package validate
 import (
 	"bytes"
 	"encoding/json"
+	"math"
+	"math/big"
 	"net"
 	"net/url"
 	"reflect"
@@ -63,6 +65,7 @@ var (
 	rxNumber    = regexp.MustCompile("^[0-9]+$")
 	rxInt       = regexp.MustCompile(Int)
 	rxFloat     = regexp.MustCompile(Float)
+	rxDecimal   = regexp.MustCompile(`^[-+]?\d+(\.\d+)?$`)
 	rxCnMobile  = regexp.MustCompile(`^1\d{10}$`)
 	rxHexColor  = regexp.MustCompile("^#?([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$")
 	rxRGBColor  = regexp.MustCompile(RGBColor)
@@ -102,6 +105,16 @@ type funcMeta struct {
 	isInternal bool
 	// last arg is like "... interface{}"
 	isVariadic bool
+	// first arg is context.Context. eg: func(ctx context.Context, val interface{}) bool
+	withCtx bool
+}
+
+// argType get the func arg type by index, the context.Context arg is excluded.
+func (fm *funcMeta) argType(i int) reflect.Type {
+	if fm.withCtx {
+		i++
+	}
+	return fm.fv.Type().In(i)
 }
 
 func (fm *funcMeta) checkArgNum(argNum int, name string) {
@@ -128,16 +141,18 @@ func newFuncMeta(name string, isInternal bool, fv reflect.Value) *funcMeta {
 	fm.numOut = ft.NumOut() // return arg num of the func
 	fm.isVariadic = ft.IsVariadic()
 
+	// first arg is context.Context, exclude it from the arg num.
+	if fm.numIn > 1 && ft.In(0) == ctxType {
+		fm.withCtx = true
+		fm.numIn--
+	}
+
 	return fm
 }
 
 // ValidatorName get real validator name.
 func ValidatorName(name string) string {
-	if rName, ok := validatorAliases[name]; ok {
-		return rName
-	}
-
-	return name
+	return defRegistry.ValidatorName(name)
 }
 
 // AddValidators to the global validators map
@@ -154,16 +169,12 @@ func AddValidators(m map[string]interface{}) {
 //		return true
 //	})
 func AddValidator(name string, checkFunc interface{}) {
-	fv := checkValidatorFunc(name, checkFunc)
-
-	validators[name] = 2 // custom
-	validatorValues[name] = fv
-	validatorMetas[name] = newFuncMeta(name, false, fv)
+	defRegistry.AddValidator(name, checkFunc)
 }
 
-// Validators get all validator names
+// Validators get all validator names. returns a copy of the names map.
 func Validators() map[string]int8 {
-	return validators
+	return defRegistry.Validators()
 }
 
 /*************************************************************
@@ -184,6 +195,26 @@ func (v *Validation) Required(field string, val interface{}) bool {
 	return !IsEmpty(val)
 }
 
+// RequiredKey field under validation must be present, the value can be empty. eg: 0, false, ""
+//
+// Usage:
+//
+//	v.StringRule("count", "requiredKey")
+func (v *Validation) RequiredKey(field string, val interface{}) bool {
+	if val != nil {
+		return true
+	}
+
+	// check file
+	fd, ok := v.data.(*FormData)
+	if ok && fd.HasFile(field) {
+		return true
+	}
+
+	_, ok = v.Get(field)
+	return ok
+}
+
 // RequiredIf field under validation must be present and not empty if the anotherField field is equal to any value.
 func (v *Validation) RequiredIf(field string, val interface{}, kvs ...string) bool {
 	// format error
@@ -299,6 +330,12 @@ func (v *Validation) EqField(val interface{}, dstField string) bool {
 		return false
 	}
 
+	// compare the time value. eg: time.Time with date string
+	if isTimeValue(val) || isTimeValue(dstVal) {
+		ret, err := compareValues(val, dstVal, v.LexicalCompare)
+		return err == nil && ret == 0
+	}
+
 	// return val == dstVal
 	return IsEqual(val, dstVal)
 }
@@ -311,6 +348,12 @@ func (v *Validation) NeField(val interface{}, dstField string) bool {
 		return false
 	}
 
+	// compare the time value. eg: time.Time with date string
+	if isTimeValue(val) || isTimeValue(dstVal) {
+		ret, err := compareValues(val, dstVal, v.LexicalCompare)
+		return err == nil && ret != 0
+	}
+
 	// return val != dstVal
 	return !IsEqual(val, dstVal)
 }
@@ -323,7 +366,7 @@ func (v *Validation) GtField(val interface{}, dstField string) bool {
 		return false
 	}
 
-	return valueCompare(val, dstVal, "gt")
+	return valueCompare(val, dstVal, "gt", v.LexicalCompare)
 }
 
 // GteField value should GTE the dst field value
@@ -334,7 +377,7 @@ func (v *Validation) GteField(val interface{}, dstField string) bool {
 		return false
 	}
 
-	return valueCompare(val, dstVal, "gte")
+	return valueCompare(val, dstVal, "gte", v.LexicalCompare)
 }
 
 // LtField value should LT the dst field value
@@ -345,10 +388,10 @@ func (v *Validation) LtField(val interface{}, dstField string) bool {
 		return false
 	}
 
-	return valueCompare(val, dstVal, "lt")
+	return valueCompare(val, dstVal, "lt", v.LexicalCompare)
 }
 
-// LteField value should LTE the dst field value(for int, string)
+// LteField value should LTE the dst field value
 func (v *Validation) LteField(val interface{}, dstField string) bool {
 	// get dst field value.
 	dstVal, has := v.Get(dstField)
@@ -356,7 +399,7 @@ func (v *Validation) LteField(val interface{}, dstField string) bool {
 		return false
 	}
 
-	return valueCompare(val, dstVal, "lte")
+	return valueCompare(val, dstVal, "lte", v.LexicalCompare)
 }
 
 /*************************************************************
@@ -498,6 +541,9 @@ func IsUint(val interface{}) bool {
 	case string:
 		_, err := strconv.ParseUint(typVal, 10, 32)
 		return err == nil
+	case json.Number:
+		_, err := strconv.ParseUint(string(typVal), 10, 32)
+		return err == nil
 	}
 	return false
 }
@@ -515,7 +561,7 @@ func IsBool(val interface{}) bool {
 	return false
 }
 
-// IsFloat check. allow: floatX, string
+// IsFloat check. allow: floatX, string, json.Number
 func IsFloat(val interface{}) bool {
 	if val == nil {
 		return false
@@ -526,6 +572,138 @@ func IsFloat(val interface{}) bool {
 		return true
 	case string:
 		return rv != "" && rxFloat.MatchString(rv)
+	case json.Number:
+		return rv != "" && rxFloat.MatchString(string(rv))
+	}
+	return false
+}
+
+// IsDecimal check the value is a decimal number, and support precision and scale check.
+// allow: intX, uintX, floatX, string, json.Number, big.Int and big.Float.
+// Usage:
+// 	ok := IsDecimal(val)
+// 	ok := IsDecimal(val, 10) // DECIMAL(10), same as DECIMAL(10,0)
+// 	ok := IsDecimal(val, 20, 2) // DECIMAL(20,2), max is 999999999999999999.99
+func IsDecimal(val interface{}, precisionAndScale ...int) bool {
+	str, ok := toDecimalString(val)
+	if !ok || !rxDecimal.MatchString(str) {
+		return false
+	}
+
+	argLn := len(precisionAndScale)
+	if argLn == 0 { // only check type
+		return true
+	}
+
+	precision, scale := precisionAndScale[0], 0
+	if argLn > 1 {
+		scale = precisionAndScale[1]
+	}
+	if precision <= 0 || scale < 0 || scale > precision {
+		return false
+	}
+
+	intPart := strings.TrimLeft(str, "+-")
+	fracPart := ""
+	if pos := strings.IndexByte(intPart, '.'); pos > -1 {
+		intPart, fracPart = intPart[:pos], intPart[pos+1:]
+	}
+
+	intPart = strings.TrimLeft(intPart, "0")
+	fracPart = strings.TrimRight(fracPart, "0")
+	return len(fracPart) <= scale && len(intPart) <= precision-scale
+}
+
+// toDecimalString convert number value to plain decimal string, without exponent.
+func toDecimalString(val interface{}) (string, bool) {
+	switch tv := val.(type) {
+	case nil:
+		return "", false
+	case *big.Int:
+		if tv == nil {
+			return "", false
+		}
+		return tv.String(), true
+	case big.Int:
+		return tv.String(), true
+	case *big.Float:
+		if tv == nil {
+			return "", false
+		}
+		return tv.Text('f', -1), true
+	case big.Float:
+		return tv.Text('f', -1), true
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), true
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32), true
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), true
+	case reflect.String: // string, json.Number
+		return rv.String(), true
+	}
+	return "", false
+}
+
+// IsJSONType check the value is one of the JSON Schema types.
+// allow types: "null", "boolean", "string", "integer", "number", "array", "object".
+// the "integer" allow the float value without fractional part. eg: 2.0
+// Usage:
+// 	ok := IsJSONType(val, "string")
+// 	ok := IsJSONType(val, "integer", "null")
+func IsJSONType(val interface{}, types ...string) bool {
+	for _, typ := range types {
+		if isJSONType(val, typ) {
+			return true
+		}
+	}
+	return false
+}
+
+func isJSONType(val interface{}, typ string) bool {
+	if val == nil {
+		return typ == "null"
+	}
+
+	switch tv := val.(type) {
+	case json.Number, *big.Int, *big.Float, big.Int, big.Float:
+		if typ == "integer" {
+			r, ok := toRat(tv)
+			return ok && r.IsInt()
+		}
+		return typ == "number"
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return typ == "boolean"
+	case reflect.String:
+		return typ == "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return typ == "integer" || typ == "number"
+	case reflect.Float32, reflect.Float64:
+		f64 := rv.Float()
+		if math.IsNaN(f64) || math.IsInf(f64, 0) {
+			return false
+		}
+		return typ == "number" || typ == "integer" && f64 == math.Trunc(f64)
+	case reflect.Slice, reflect.Array:
+		return typ == "array"
+	case reflect.Map, reflect.Struct:
+		return typ == "object"
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return typ == "null"
+		}
+		return isJSONType(rv.Elem().Interface(), typ)
 	}
 	return false
 }
@@ -1043,63 +1221,80 @@ func IntEqual(val interface{}, wantVal int64) bool {
 	return intVal == wantVal
 }
 
-// Gt check value greater dst value. only check for: int(X), uint(X), float(X)
+// Gt check value greater dst value. only check for: int(X), uint(X), float(X) and numeric string.
+// the float value will not be truncated. use GtNumber() for the float dst value.
 func Gt(val interface{}, dstVal int64) bool {
-	if flt, ok := val.(float64); ok {
-		return flt > float64(dstVal)
-	}
-
-	if flt, ok := val.(float32); ok {
-		return flt > float32(dstVal)
-	}
-
-	intVal, err := mathutil.Int64(val)
-	if err != nil {
-		return false
-	}
-
-	return intVal > dstVal
+	return GtNumber(nilAsZero(val), dstVal)
 }
 
 // Min check value greater or equal dst value, alias `Gte`.
-// only check for: int(X), uint(X), float(X).
+// only check for: int(X), uint(X), float(X) and numeric string. use MinNumber() for the float min value.
 func Min(val interface{}, min int64) bool {
-	intVal, err := mathutil.Int64(val)
-	if err != nil {
-		return false
-	}
-
-	return intVal >= min
+	return MinNumber(nilAsZero(val), min)
 }
 
-// Lt less than dst value. only check for: int(X), uint(X), float(X).
+// Lt less than dst value. only check for: int(X), uint(X), float(X) and numeric string.
 func Lt(val interface{}, dstVal int64) bool {
-	intVal, err := mathutil.Int64(val)
-	if err != nil {
-		return false
-	}
-
-	return intVal < dstVal
+	return LtNumber(nilAsZero(val), dstVal)
 }
 
-// Max less than or equal dst value, alias `Lte`. check for: int(X), uint(X), float(X).
+// Max less than or equal dst value, alias `Lte`. check for: int(X), uint(X), float(X) and numeric string.
 func Max(val interface{}, max int64) bool {
-	intVal, err := mathutil.Int64(val)
-	if err != nil {
-		return false
+	return MaxNumber(nilAsZero(val), max)
+}
+
+// Between int value in the given range. use BetweenNumber() for the float range.
+func Between(val interface{}, min, max int64) bool {
+	return BetweenNumber(nilAsZero(val), min, max)
+}
+
+// the nil value is as 0 in the int64 compare functions. keep compatible with the old version.
+func nilAsZero(val interface{}) interface{} {
+	if val == nil {
+		return 0
 	}
+	return val
+}
 
-	return intVal <= max
+// GtNumber check value greater dst value. only check for: int(X), uint(X), float(X) and numeric string.
+// the dstVal allow int(X), uint(X), float(X) or numeric string. eg: 2, 0.01, "0.01"
+//
+// Usage:
+//
+//	GtNumber(2.5, 2.1) // true
+//	GtNumber(nil, -1) // false
+func GtNumber(val, dstVal interface{}) bool {
+	ret, ok := compareNumber(val, dstVal)
+	return ok && ret > 0
 }
 
-// Between int value in the given range.
-func Between(val interface{}, min, max int64) bool {
-	intVal, err := mathutil.Int64(val)
-	if err != nil {
+// MinNumber check value greater or equal dst value. the min allow int, float value or numeric string.
+func MinNumber(val, min interface{}) bool {
+	ret, ok := compareNumber(val, min)
+	return ok && ret >= 0
+}
+
+// LtNumber less than dst value. the dstVal allow int, float value or numeric string.
+func LtNumber(val, dstVal interface{}) bool {
+	ret, ok := compareNumber(val, dstVal)
+	return ok && ret < 0
+}
+
+// MaxNumber less than or equal dst value. the max allow int, float value or numeric string.
+func MaxNumber(val, max interface{}) bool {
+	ret, ok := compareNumber(val, max)
+	return ok && ret <= 0
+}
+
+// BetweenNumber value in the given range. the min, max allow int, float value or numeric string.
+func BetweenNumber(val, min, max interface{}) bool {
+	ret, ok := compareNumber(val, min)
+	if !ok || ret < 0 {
 		return false
 	}
 
-	return intVal >= min && intVal <= max
+	ret, ok = compareNumber(val, max)
+	return ok && ret <= 0
 }
 
 /*************************************************************
@@ -1117,6 +1312,13 @@ func convert(val interface{}) (value interface{}, err error) {
 		value = v.Int()
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		value = v.Uint()
+	case reflect.Float32, reflect.Float64:
+		// the float without fractional part. eg: the number decoded from JSON
+		f64 := v.Float()
+		if f64 != math.Trunc(f64) || math.Abs(f64) > math.MaxInt64 {
+			return nil, errConvertFail
+		}
+		value = int64(f64)
 	default:
 		err = errConvertFail
 	}
@@ -1125,6 +1327,7 @@ func convert(val interface{}) (value interface{}, err error) {
 }
 
 // Enum value(int(X),string) should be in the given enum(strings, ints, uints).
+// the float value without fractional part is as int. eg: 2.0
 func Enum(val, enum interface{}) bool {
 	if val == nil || enum == nil {
 		return false
@@ -1326,3 +1529,139 @@ func AfterDate(srcDate, dstDate string) bool {
 
 	return st.After(dt)
 }
+
+/*************************************************************
+ * time.Time and time.Duration validators
+ *************************************************************/
+
+// After check the time value is after the given time. the value can be time.Time or date string.
+// the dstTime allow: "now", "now+1h", "now-72h" or date string. "now" is get by the Validation.Clock
+//
+// Usage:
+// 	v.StringRule("startAt", "after:now")
+func (v *Validation) After(val interface{}, dstTime string) bool {
+	st, dt, ok := v.timeValues(val, dstTime)
+	return ok && st.After(dt)
+}
+
+// Before check the time value is before the given time. see After()
+//
+// Usage:
+// 	v.StringRule("birthday", "before:2030-01-01")
+func (v *Validation) Before(val interface{}, dstTime string) bool {
+	st, dt, ok := v.timeValues(val, dstTime)
+	return ok && st.Before(dt)
+}
+
+// WithinLast check the time value is within the last duration, and not after now.
+//
+// Usage:
+// 	v.StringRule("loginAt", "withinLast:72h")
+func (v *Validation) WithinLast(val interface{}, duration string) bool {
+	t, err := toTime(indirectValue(val))
+	if err != nil {
+		return false
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil || d < 0 {
+		return false
+	}
+
+	now := v.now()
+	return !t.After(now) && !t.Before(now.Add(-d))
+}
+
+func (v *Validation) timeValues(val interface{}, dstTime string) (st, dt time.Time, ok bool) {
+	st, err := toTime(indirectValue(val))
+	if err != nil {
+		return
+	}
+
+	dt, err = v.parseTimeArg(dstTime)
+	return st, dt, err == nil
+}
+
+// parseTimeArg parse the time argument. allow: "now", "now+1h", "now-72h" or date string
+func (v *Validation) parseTimeArg(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "now") {
+		return strutil.ToTime(s)
+	}
+
+	now := v.now()
+	if s = strings.TrimSpace(s[3:]); s == "" {
+		return now, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return now, err
+	}
+	return now.Add(d), nil
+}
+
+// IsWeekday check the time value is on the weekday. the value can be time.Time or date string.
+// default the weekdays is Monday to Friday, can custom by the days. eg: "sat", "sunday"
+//
+// Usage:
+// 	IsWeekday(t)
+// 	IsWeekday(t, "sat", "sun")
+func IsWeekday(val interface{}, days ...string) bool {
+	t, err := toTime(indirectValue(val))
+	if err != nil {
+		return false
+	}
+
+	wd := t.Weekday()
+	if len(days) == 0 {
+		return wd != time.Saturday && wd != time.Sunday
+	}
+
+	for _, day := range days {
+		day = strings.ToLower(strings.TrimSpace(day))
+		if len(day) >= 3 && strings.HasPrefix(strings.ToLower(wd.String()), day[:3]) {
+			return true
+		}
+	}
+	return false
+}
+
+// MinDuration check the duration value is greater than or equal to the min.
+// the value can be time.Duration, int(nanoseconds) or duration string. eg: "1h30m"
+//
+// Usage:
+// 	MinDuration(d, "1s")
+func MinDuration(val interface{}, min string) bool {
+	d, ok := toDuration(val)
+	if !ok {
+		return false
+	}
+
+	minD, err := time.ParseDuration(min)
+	return err == nil && d >= minD
+}
+
+// MaxDuration check the duration value is less than or equal to the max. see MinDuration()
+func MaxDuration(val interface{}, max string) bool {
+	d, ok := toDuration(val)
+	if !ok {
+		return false
+	}
+
+	maxD, err := time.ParseDuration(max)
+	return err == nil && d <= maxD
+}
+
+func toDuration(val interface{}) (time.Duration, bool) {
+	switch tv := indirectValue(val).(type) {
+	case time.Duration:
+		return tv, true
+	case string:
+		d, err := time.ParseDuration(tv)
+		return d, err == nil
+	}
+
+	i64, _, isInt, err := toNumber(indirectValue(val))
+	return time.Duration(i64), err == nil && isInt
+}
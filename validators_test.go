@@ -1,8 +1,11 @@
 package validate
 
 import (
+	"encoding/json"
+	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -261,6 +264,132 @@ func TestMax(t *testing.T) {
 	is.False(Max(int64(3), 2))
 }
 
+func TestFloatBounds(t *testing.T) {
+	is := assert.New(t)
+
+	// float value will not be truncated
+	is.False(Min(0.5, 1))
+	// the results were changed, they were true, true and false before
+	is.False(Max(1.5, 1))
+	is.False(Between(1.5, 0, 1))
+	is.True(Gt("1.5", 1))
+	is.False(Max(2.5, 2))
+	is.False(Lt(2.9, 2))
+	is.True(Between(1.5, 1, 2))
+	is.False(GtNumber(2.1, 2.1))
+	is.True(MaxNumber(2.0, 2))
+	is.True(MinNumber(0.01, "0.01"))
+	is.True(MinNumber("0.02", 0.01))
+	is.False(MinNumber(float32(0.001), 0.01))
+	is.True(BetweenNumber(1.5, "1.25", 1.75))
+	is.False(BetweenNumber(1.8, 1, 1.75))
+	is.False(MinNumber(1, "invalid"))
+
+	// nil is not a number
+	is.False(MaxNumber(nil, 3))
+	is.False(LtNumber(nil, 3))
+	is.False(GtNumber(nil, -1))
+	is.False(BetweenNumber(nil, -1, 1))
+	is.False(MinNumber(1, nil))
+	is.False(LtNumber(1, nil))
+
+	// named type
+	type price float64
+	is.True(Gt(price(1.5), 1))
+	is.True(GtNumber(price(1.5), 1.25))
+
+	// large int keep the precision
+	is.True(Gt(int64(1<<60+1), int64(1<<60)))
+
+	v := Map(M{"price": 0.005, "rate": "1.5", "amount": 10})
+	v.StopOnError = false
+	v.StringRules(MS{
+		"price":  "min:0.01",
+		"rate":   "minFloat:0.5|maxFloat:1.25",
+		"amount": "between:0.5,9.5",
+	})
+	is.False(v.Validate())
+	is.Equal("price min value is 0.01", v.Errors.FieldOne("price"))
+	is.Equal("rate max value is 1.25", v.Errors.FieldOne("rate"))
+	is.Equal("amount value must be in the range 0.5 - 9.5", v.Errors.FieldOne("amount"))
+
+	v = Map(M{"price": 0.01, "rate": 1.2, "amount": 9.5})
+	v.StringRules(MS{
+		"price":  "min:0.01|lt:1",
+		"rate":   "minFloat:0.5|maxFloat:1.25|gt:1.1",
+		"amount": "between:0.5,9.5",
+	})
+	is.True(v.Validate())
+}
+
+func TestIsDecimal(t *testing.T) {
+	is := assert.New(t)
+
+	is.True(IsDecimal("12345678901234567890.12"))
+	is.True(IsDecimal(json.Number("-0.5")))
+	is.True(IsDecimal(12))
+	is.True(IsDecimal(1.25))
+	is.True(IsDecimal(big.NewInt(10)))
+	is.False(IsDecimal("1e5"))
+	is.False(IsDecimal("1."))
+	is.False(IsDecimal("abc"))
+	is.False(IsDecimal(nil))
+	is.False(IsDecimal(true))
+
+	// DECIMAL(20,2)
+	is.True(IsDecimal("999999999999999999.99", 20, 2))
+	is.True(IsDecimal("000123.4500", 5, 2))
+	is.False(IsDecimal("9999999999999999999.99", 20, 2))
+	is.False(IsDecimal("1.234", 20, 2))
+	// DECIMAL(3) is same as DECIMAL(3,0)
+	is.True(IsDecimal(999, 3))
+	is.False(IsDecimal(1000, 3))
+	is.False(IsDecimal("1.5", 3))
+	// invalid precision and scale
+	is.False(IsDecimal("1.5", 0))
+	is.False(IsDecimal("1.5", 2, 3))
+
+	v := Map(M{"price": "1234.567", "amount": "12345678901234567890.12"})
+	v.StopOnError = false
+	v.StringRules(MS{
+		"price":  "isDecimal:6,2",
+		"amount": "decimal:22,2",
+	})
+	is.False(v.Validate())
+	is.Equal([]string{"price"}, v.FieldErrors().Fields())
+	is.Equal("price value must be a decimal number with at most 6 digits and 2 decimal places", v.Errors.One())
+}
+
+func TestBigNumberBounds(t *testing.T) {
+	is := assert.New(t)
+
+	huge, _ := new(big.Int).SetString("12345678901234567890", 10)
+	is.True(MinNumber(huge, "12345678901234567890"))
+	is.True(GtNumber(huge, "12345678901234567889.99"))
+	is.False(GtNumber(huge, huge))
+	is.True(MaxNumber(huge, json.Number("12345678901234567890.01")))
+	is.True(BetweenNumber(big.NewFloat(1.5), 1, "2"))
+	is.False(LtNumber(new(big.Float).SetInf(false), 1))
+
+	// no float precision losing
+	is.False(MinNumber("12345678901234567890.12", "12345678901234567890.13"))
+	is.True(GtNumber(json.Number("9007199254740993"), json.Number("9007199254740992")))
+	is.True(MinNumber(json.Number("10"), 10))
+	is.True(GtNumber(json.Number("1e3"), 999))
+	is.True(MaxNumber(float32(0.1), "0.1"))
+	is.True(MinNumber(uint64(1<<63), int64(1<<62)))
+	is.False(MinNumber(json.Number("abc"), 1))
+
+	v := Map(M{"amount": json.Number("12345678901234567890.12")})
+	v.StringRule("amount", "min:12345678901234567890.13")
+	is.False(v.Validate())
+	is.Equal("amount min value is 12345678901234567890.13", v.Errors.One())
+
+	v = Map(M{"amount": huge})
+	v.StringRule("amount", "between:1,12345678901234567890")
+	is.True(v.Validate())
+}
+
 // ------------------ string check ------------------
 
 func TestStringCheck(t *testing.T) {
@@ -617,6 +746,47 @@ func TestEnumAndNotIn(t *testing.T) {
 		is.True(NotIn(val, list))
 		is.False(Enum(val, list))
 	}
+
+	// the float without fractional part. eg: decoded from JSON
+	is.True(Enum(2.0, []string{"1", "2"}))
+	is.True(Enum(float32(3), []int{3}))
+	is.False(Enum(2.5, []int{2, 3}))
+}
+
+func TestIsJSONType(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		val   interface{}
+		types []string
+		want  bool
+	}{
+		{nil, []string{"null"}, true},
+		{nil, []string{"string"}, false},
+		{"abc", []string{"string"}, true},
+		{true, []string{"boolean"}, true},
+		{12, []string{"integer"}, true},
+		{uint(12), []string{"number"}, true},
+		{2.0, []string{"integer"}, true},
+		{2.5, []string{"integer"}, false},
+		{2.5, []string{"number"}, true},
+		{json.Number("12345678901234567890"), []string{"integer"}, true},
+		{json.Number("1.5"), []string{"integer"}, false},
+		{big.NewInt(1), []string{"number"}, true},
+		{[]string{"a"}, []string{"array"}, true},
+		{M{"a": 1}, []string{"object"}, true},
+		{"abc", []string{"integer", "null", "string"}, true},
+		{12, []string{"string", "boolean"}, false},
+	}
+
+	for _, tt := range tests {
+		is.Equal(tt.want, IsJSONType(tt.val, tt.types...), tt)
+	}
+
+	v := Map(M{"age": 2.5})
+	v.StringRule("age", "jsonType:integer,null")
+	is.False(v.Validate())
+	is.Equal("age value must be of the JSON type [integer null]", v.Errors.One())
 }
 
 func TestDateCheck(t *testing.T) {
@@ -654,3 +824,94 @@ func TestDateCheck(t *testing.T) {
 	is.False(AfterOrEqualDate("invalid", "2018-10-26"))
 	is.False(AfterOrEqualDate("2018-10-25", "invalid"))
 }
+
+func TestTimeValidators(t *testing.T) {
+	is := assert.New(t)
+	// Sunday
+	now := time.Date(2021, 5, 2, 10, 0, 0, 0, time.UTC)
+
+	// weekday
+	is.True(IsWeekday(now.AddDate(0, 0, 1)))
+	is.True(IsWeekday("2021-05-03"))
+	is.False(IsWeekday(now))
+	is.True(IsWeekday(now, "sat", "Sunday"))
+	is.False(IsWeekday(now, "mon"))
+	is.False(IsWeekday("invalid"))
+	is.False(IsWeekday(23))
+
+	// duration
+	is.True(MinDuration(time.Second, "1s"))
+	is.True(MinDuration("1m", "1s"))
+	is.True(MinDuration(int64(2e9), "1s"))
+	is.False(MinDuration(time.Millisecond, "1s"))
+	is.False(MinDuration("abc", "1s"))
+	is.False(MinDuration(time.Second, "abc"))
+	is.True(MaxDuration(time.Hour, "24h"))
+	is.False(MaxDuration(25*time.Hour, "24h"))
+
+	// relative time by the clock
+	v := Map(M{})
+	v.Clock = func() time.Time { return now }
+	is.True(v.After(now.Add(time.Second), "now"))
+	is.False(v.After(now, "now"))
+	is.True(v.After(now, "now-1h"))
+	is.True(v.Before(now, "now+1h"))
+	is.True(v.Before(&now, "2030-01-01"))
+	is.True(v.Before("2021-05-01", "now"))
+	is.False(v.Before(now, "invalid"))
+	is.False(v.After(23, "now"))
+	is.True(v.WithinLast(now.Add(-71*time.Hour), "72h"))
+	is.True(v.WithinLast(now, "72h"))
+	is.False(v.WithinLast(now.Add(-73*time.Hour), "72h"))
+	is.False(v.WithinLast(now.Add(time.Minute), "72h"))
+	is.False(v.WithinLast(now, "abc"))
+}
+
+func TestTimeValidators_rules(t *testing.T) {
+	is := assert.New(t)
+	now := time.Date(2021, 5, 2, 10, 0, 0, 0, time.UTC)
+
+	type event struct {
+		StartAt  time.Time     `validate:"required|after:now|before:2030-01-01"`
+		LoginAt  time.Time     `validate:"withinLast:72h"`
+		WorkDay  time.Time     `validate:"weekday"`
+		Timeout  time.Duration `validate:"minDuration:1s|maxDuration:24h"`
+		Interval string        `validate:"minDuration:1s"`
+	}
+
+	e := &event{
+		StartAt:  now.Add(time.Hour),
+		LoginAt:  now.Add(-time.Hour),
+		WorkDay:  now.AddDate(0, 0, 1),
+		Timeout:  time.Minute,
+		Interval: "1m30s",
+	}
+	v := Struct(e)
+	v.Clock = func() time.Time { return now }
+	is.True(v.Validate())
+
+	e = &event{
+		StartAt:  now.Add(-time.Hour),
+		LoginAt:  now.Add(-100 * time.Hour),
+		WorkDay:  now,
+		Timeout:  48 * time.Hour,
+		Interval: "10ms",
+	}
+	v = Struct(e)
+	v.StopOnError = false
+	v.Clock = func() time.Time { return now }
+	is.False(v.Validate())
+	is.Equal([]string{"StartAt", "LoginAt", "WorkDay", "Timeout", "Interval"}, v.FieldErrors().Fields())
+	is.Equal("StartAt value should be after now", v.Errors.FieldOne("StartAt"))
+	is.Equal("LoginAt value should be within the last 72h", v.Errors.FieldOne("LoginAt"))
+	is.Equal("WorkDay value should be on the weekday", v.Errors.FieldOne("WorkDay"))
+	is.Equal("Timeout duration should be at most 24h", v.Errors.FieldOne("Timeout"))
+
+	// schema keep the clock
+	s := NewSchema(func(v *Validation) {
+		v.Clock = func() time.Time { return now }
+		v.StringRule("at", "after:now")
+	})
+	is.True(s.Validate(FromMap(M{"at": now.Add(time.Second)}), "").IsOK())
+	is.False(s.Validate(FromMap(M{"at": now}), "").IsOK())
+}